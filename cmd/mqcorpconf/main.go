@@ -0,0 +1,80 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// mqcorpconf loads a directory of corpus configurations (see corp.LoadDir)
+// and writes fully resolved configurations (i.e. with defaults, overrides
+// and base setups applied) as JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/czcorpus/mquery-common/tagset"
+)
+
+func main() {
+	env := flag.String("env", "", "environment whose override files should be applied")
+	tagsetDir := flag.String("tagsets", "", "directory with additional tagset definitions")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] CONF_DIR [CORPUS_ID...]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	tagsets := tagset.NewRegistry()
+	if *tagsetDir != "" {
+		if err := tagsets.LoadDir(*tagsetDir); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load tagsets: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	reg, err := corp.LoadDir(flag.Arg(0), corp.LoadOptions{Env: *env, TagsetValidator: tagsets.Validate})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load corpora:\n%s\n", err)
+		os.Exit(1)
+	}
+	ids := flag.Args()[1:]
+	if len(ids) == 0 {
+		ids = reg.IDs()
+	}
+	ans := make([]*corp.CorpusSetup, 0, len(ids))
+	for _, id := range ids {
+		setup, ok := reg.Get(id)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown corpus %s\n", id)
+			os.Exit(1)
+		}
+		ans = append(ans, setup)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	var out any = ans
+	if len(ans) == 1 {
+		out = ans[0]
+	}
+	if err := enc.Encode(out); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write configuration: %s\n", err)
+		os.Exit(1)
+	}
+}
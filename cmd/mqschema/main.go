@@ -0,0 +1,54 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// mqschema writes JSON Schema or OpenAPI component definitions
+// of the mquery-common shared types.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/czcorpus/mquery-common/schema"
+)
+
+func main() {
+	format := flag.String("format", string(schema.FormatOpenAPI), "output format (openapi, jsonschema)")
+	output := flag.String("o", "", "output file (stdout if empty)")
+	title := flag.String("title", "mquery-common types", "document title")
+	version := flag.String("version", "1.0.0", "document version (OpenAPI only)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	data, err := schema.Generate(schema.Format(*format), *title, *version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate schema: %s\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write schema: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package collocation calculates collocation candidates
+// and their association scores from parsed concordances.
+package collocation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// Window specifies how many tokens to the left and to the right
+// of the KWIC are searched for collocates.
+type Window struct {
+	Left  int `json:"left"`
+	Right int `json:"right"`
+}
+
+// NewWindow creates a window for the corpus. In case the corpus
+// has MaximumTokenContextWindow configured, the window is clamped
+// so it does not exceed the maximum context.
+func NewWindow(setup *corp.CorpusSetup, left, right int) (Window, error) {
+	if left < 0 || right < 0 {
+		return Window{}, fmt.Errorf("invalid collocation window %d, %d", left, right)
+	}
+	ans := Window{Left: left, Right: right}
+	if setup != nil && setup.MaximumTokenContextWindow > 0 {
+		maxLft, maxRgt := setup.MaximumTokenContextWindow.LeftAndRight()
+		ans.Left = min(ans.Left, maxLft)
+		ans.Right = min(ans.Right, maxRgt)
+	}
+	return ans, nil
+}
+
+// ------
+
+// Candidates contains co-occurrence counts of attribute values
+// found within a window around the KWIC.
+type Candidates struct {
+	Attr   string `json:"attr"`
+	Window Window `json:"window"`
+
+	// NodeFreq is the number of lines with a KWIC which is used
+	// as the default frequency of the node.
+	NodeFreq int64 `json:"nodeFreq"`
+
+	// Coocs maps attribute values to their co-occurrence counts
+	Coocs map[string]int64 `json:"coocs"`
+}
+
+// Values returns all the collocate candidates sorted. This is
+// typically used to obtain the values' corpus frequencies.
+func (c *Candidates) Values() []string {
+	ans := make([]string, 0, len(c.Coocs))
+	for k := range c.Coocs {
+		ans = append(ans, k)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
+// CountCandidates counts co-occurrences of `attr` values within
+// the window around the KWIC. A value is counted at most once per line
+// (i.e. a value occurring twice in a line's window counts as a single
+// co-occurrence) so no co-occurrence count can exceed the node frequency.
+// Lines without a KWIC are skipped. Negative window sizes are treated
+// as zero.
+func CountCandidates(lines []concordance.Line, attr string, win Window) *Candidates {
+	win.Left = max(0, win.Left)
+	win.Right = max(0, win.Right)
+	ans := &Candidates{
+		Attr:   attr,
+		Window: win,
+		Coocs:  make(map[string]int64),
+	}
+	seen := make(map[string]bool)
+	for i := range lines {
+		if len(lines[i].KWICTokens()) == 0 {
+			continue
+		}
+		ans.NodeFreq++
+		clear(seen)
+		for _, tok := range lines[i].LeftContext(win.Left) {
+			seen[tok.Attr(attr)] = true
+		}
+		for _, tok := range lines[i].RightContext(win.Right) {
+			seen[tok.Attr(attr)] = true
+		}
+		for v := range seen {
+			ans.Coocs[v]++
+		}
+	}
+	return ans
+}
+
+// ------
+
+// ScoreParams contains corpus data needed to calculate association
+// measures of collocation candidates.
+type ScoreParams struct {
+
+	// CorpusSize is a size of the corpus (or subcorpus) in tokens
+	CorpusSize int64
+
+	// NodeFreq is a frequency of the node (= searched expression).
+	// If zero, Candidates.NodeFreq is used.
+	NodeFreq int64
+
+	// CollFreqs contains corpus frequencies of candidate values.
+	// Candidates without a frequency are skipped.
+	CollFreqs map[string]int64
+
+	// MinCoocFreq skips candidates with lower co-occurrence count
+	MinCoocFreq int64
+}
+
+// Collocation is a scored collocation candidate
+type Collocation struct {
+	Value    string              `json:"value"`
+	CoocFreq int64               `json:"coocFreq"`
+	CollFreq int64               `json:"collFreq"`
+	Scores   map[Measure]float64 `json:"scores"`
+}
+
+// Score calculates provided association measures of all the candidates.
+// The result is sorted by the first measure in descending order.
+func (c *Candidates) Score(params ScoreParams, measures ...Measure) ([]*Collocation, error) {
+	if len(measures) == 0 {
+		return nil, fmt.Errorf("no association measure specified")
+	}
+	for _, m := range measures {
+		if err := m.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if params.CorpusSize <= 0 {
+		return nil, fmt.Errorf("invalid corpus size %d", params.CorpusSize)
+	}
+	nodeFreq := params.NodeFreq
+	if nodeFreq == 0 {
+		nodeFreq = c.NodeFreq
+	}
+	if nodeFreq <= 0 {
+		return nil, fmt.Errorf("invalid node frequency %d", nodeFreq)
+	}
+	ans := make([]*Collocation, 0, len(c.Coocs))
+	for _, value := range c.Values() {
+		fAB := c.Coocs[value]
+		fB, ok := params.CollFreqs[value]
+		if !ok || fAB < params.MinCoocFreq {
+			continue
+		}
+		if fB < fAB {
+			return nil, fmt.Errorf(
+				"inconsistent data for `%s`: corpus frequency %d is lower than co-occurrence count %d",
+				value, fB, fAB)
+		}
+		if nodeFreq < fAB {
+			return nil, fmt.Errorf(
+				"inconsistent data for `%s`: node frequency %d is lower than co-occurrence count %d",
+				value, nodeFreq, fAB)
+		}
+		if nodeFreq+fB-fAB > params.CorpusSize {
+			return nil, fmt.Errorf(
+				"inconsistent data for `%s`: frequencies exceed corpus size %d",
+				value, params.CorpusSize)
+		}
+		coll := &Collocation{
+			Value:    value,
+			CoocFreq: fAB,
+			CollFreq: fB,
+			Scores:   make(map[Measure]float64, len(measures)),
+		}
+		for _, m := range measures {
+			coll.Scores[m] = m.Calc(fAB, nodeFreq, fB, params.CorpusSize)
+		}
+		ans = append(ans, coll)
+	}
+	SortBy(ans, measures[0])
+	return ans, nil
+}
+
+// SortBy sorts collocations by a measure in descending order.
+// Ties are resolved by co-occurrence counts and values.
+func SortBy(colls []*Collocation, m Measure) {
+	sort.SliceStable(colls, func(i, j int) bool {
+		a, b := colls[i], colls[j]
+		if a.Scores[m] != b.Scores[m] {
+			return a.Scores[m] > b.Scores[m]
+		}
+		if a.CoocFreq != b.CoocFreq {
+			return a.CoocFreq > b.CoocFreq
+		}
+		return a.Value < b.Value
+	})
+}
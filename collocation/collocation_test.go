@@ -0,0 +1,138 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collocation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testLine1 = `#1 {refs:end}a {} /a/J attr  velmi {} /velmi/Dg attr  dobrý {} /dobrý/AA attr` +
+		`  den {col0 coll} /den/NN attr  , {} /,/Z attr  pane {} /pan/NN attr`
+	testLine2 = `#2 {refs:end}dobrý {} /dobrý/AA attr  den {col0 coll} /den/NN attr` +
+		`  dobrý {} /dobrý/AA attr`
+	testLine3 = `#3 {refs:end}ten {} /ten/PD attr  den {col0 coll} /den/NN attr  ,` +
+		` {} /,/Z attr`
+)
+
+func testLines() []concordance.Line {
+	p := concordance.NewLineParser([]string{"word", "lemma", "tag"})
+	return p.Parse([]string{testLine1, testLine2, testLine3})
+}
+
+func TestNewWindowRespectsSetup(t *testing.T) {
+	setup := &corp.CorpusSetup{MaximumTokenContextWindow: 5}
+	win, err := NewWindow(setup, 5, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, Window{Left: 2, Right: 3}, win)
+
+	win, err = NewWindow(&corp.CorpusSetup{}, 5, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, Window{Left: 5, Right: 1}, win)
+
+	_, err = NewWindow(setup, -1, 2)
+	assert.Error(t, err)
+}
+
+func TestCountCandidates(t *testing.T) {
+	cands := CountCandidates(testLines(), "lemma", Window{Left: 2, Right: 1})
+	assert.Equal(t, int64(3), cands.NodeFreq)
+	// "dobrý" occurs twice in the window of the second line
+	assert.Equal(t, int64(2), cands.Coocs["dobrý"])
+	assert.Equal(t, int64(1), cands.Coocs["velmi"])
+	assert.Equal(t, int64(2), cands.Coocs[","])
+	assert.Equal(t, int64(1), cands.Coocs["ten"])
+	assert.NotContains(t, cands.Coocs, "a")
+	assert.NotContains(t, cands.Coocs, "pan")
+	assert.Equal(t, []string{",", "dobrý", "ten", "velmi"}, cands.Values())
+}
+
+func TestMeasures(t *testing.T) {
+	// expected values calculated independently from the textbook formulas
+	assert.InDelta(t, 9.966, MeasureMI.Calc(10, 1000, 100, 10000000), 0.001)
+	assert.InDelta(t, 3.159, MeasureTScore.Calc(10, 1000, 100, 10000000), 0.001)
+	assert.InDelta(t, 8.219, MeasureLogDice.Calc(10, 1000, 100, 10000000), 0.001)
+	assert.InDelta(t, 119.308, MeasureLogLikelihood.Calc(10, 1000, 100, 10000000), 0.01)
+	assert.True(t, math.IsInf(MeasureMI.Calc(0, 1000, 100, 10000000), -1))
+	assert.Error(t, Measure("x").Validate())
+}
+
+func TestScore(t *testing.T) {
+	cands := CountCandidates(testLines(), "lemma", Window{Left: 2, Right: 1})
+	colls, err := cands.Score(
+		ScoreParams{
+			CorpusSize: 1000000,
+			CollFreqs:  map[string]int64{"dobrý": 100, ",": 50000, "velmi": 300},
+		},
+		MeasureLogDice, MeasureMI,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, colls, 3)
+	assert.Equal(t, "dobrý", colls[0].Value)
+	assert.Equal(t, "velmi", colls[1].Value)
+	assert.Equal(t, ",", colls[2].Value)
+	assert.Contains(t, colls[0].Scores, MeasureMI)
+
+	SortBy(colls, MeasureMI)
+	assert.Equal(t, "dobrý", colls[0].Value)
+}
+
+func TestScoreErrors(t *testing.T) {
+	cands := CountCandidates(testLines(), "lemma", Window{Left: 2, Right: 1})
+	_, err := cands.Score(ScoreParams{CorpusSize: 100})
+	assert.Error(t, err)
+	_, err = cands.Score(ScoreParams{}, MeasureMI)
+	assert.Error(t, err)
+	_, err = cands.Score(
+		ScoreParams{CorpusSize: 100, CollFreqs: map[string]int64{"dobrý": 1}}, MeasureMI)
+	assert.Error(t, err)
+	_, err = cands.Score(
+		ScoreParams{CorpusSize: 100, NodeFreq: 1, CollFreqs: map[string]int64{"dobrý": 10}},
+		MeasureLogLikelihood,
+	)
+	assert.ErrorContains(t, err, "node frequency 1 is lower than co-occurrence count 2")
+	_, err = cands.Score(
+		ScoreParams{CorpusSize: 10, CollFreqs: map[string]int64{"dobrý": 10}}, MeasureLogLikelihood)
+	assert.ErrorContains(t, err, "exceed corpus size")
+}
+
+func TestCoocsNotExceedingNodeFreq(t *testing.T) {
+	cands := CountCandidates(testLines(), "lemma", Window{Left: 10, Right: 10})
+	for v, fAB := range cands.Coocs {
+		assert.LessOrEqual(t, fAB, cands.NodeFreq, v)
+	}
+	colls, err := cands.Score(
+		ScoreParams{CorpusSize: 1000, CollFreqs: map[string]int64{"dobrý": 2, ",": 50}},
+		MeasureLogLikelihood,
+	)
+	assert.NoError(t, err)
+	for _, c := range colls {
+		assert.False(t, math.IsNaN(c.Scores[MeasureLogLikelihood]), c.Value)
+	}
+}
+
+func TestCountCandidatesNegativeWindow(t *testing.T) {
+	cands := CountCandidates(testLines(), "lemma", Window{Left: -2, Right: 1})
+	assert.Equal(t, Window{Left: 0, Right: 1}, cands.Window)
+	assert.Equal(t, int64(1), cands.Coocs["dobrý"])
+	assert.NotContains(t, cands.Coocs, "velmi")
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collocation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Measure is an association measure. The values are the same
+// as the ones used by Manatee-open (e.g. in KonText's collocation
+// form).
+type Measure string
+
+const (
+	MeasureTScore        Measure = "t"
+	MeasureMI            Measure = "m"
+	MeasureLogDice       Measure = "d"
+	MeasureLogLikelihood Measure = "l"
+)
+
+func (m Measure) Validate() error {
+	if m == MeasureTScore || m == MeasureMI || m == MeasureLogDice || m == MeasureLogLikelihood {
+		return nil
+	}
+	return fmt.Errorf("invalid association measure: %s", m)
+}
+
+func (m Measure) String() string {
+	return string(m)
+}
+
+// Calc calculates the measure value.
+//
+// fAB is the number of co-occurrences of the node (A) and the collocate (B),
+// fA and fB are their frequencies and n is the corpus size.
+func (m Measure) Calc(fAB, fA, fB, n int64) float64 {
+	switch m {
+	case MeasureTScore:
+		return tScore(fAB, fA, fB, n)
+	case MeasureMI:
+		return mutualInformation(fAB, fA, fB, n)
+	case MeasureLogDice:
+		return logDice(fAB, fA, fB)
+	case MeasureLogLikelihood:
+		return logLikelihood(fAB, fA, fB, n)
+	}
+	return math.NaN()
+}
+
+func tScore(fAB, fA, fB, n int64) float64 {
+	if fAB == 0 {
+		return 0
+	}
+	return (float64(fAB) - float64(fA)*float64(fB)/float64(n)) / math.Sqrt(float64(fAB))
+}
+
+func mutualInformation(fAB, fA, fB, n int64) float64 {
+	if fAB == 0 {
+		return math.Inf(-1)
+	}
+	return math.Log2(float64(fAB) * float64(n) / (float64(fA) * float64(fB)))
+}
+
+func logDice(fAB, fA, fB int64) float64 {
+	if fAB == 0 {
+		return math.Inf(-1)
+	}
+	return 14 + math.Log2(2*float64(fAB)/(float64(fA)+float64(fB)))
+}
+
+// llTerm calculates o * ln(o / e) with the limit value 0 for o == 0
+func llTerm(o, e float64) float64 {
+	if o == 0 {
+		return 0
+	}
+	return o * math.Log(o/e)
+}
+
+// logLikelihood calculates Dunning's log-likelihood (G2) from
+// a 2x2 contingency table. All the cells must be non-negative
+// (i.e. fAB <= fA, fAB <= fB and fA + fB - fAB <= n), otherwise
+// the result is NaN (see Candidates.Score for the checks).
+func logLikelihood(fAB, fA, fB, n int64) float64 {
+	o11 := float64(fAB)
+	o12 := float64(fA - fAB)
+	o21 := float64(fB - fAB)
+	o22 := float64(n - fA - fB + fAB)
+	r1, r2 := o11+o12, o21+o22
+	c1, c2 := o11+o21, o12+o22
+	fn := float64(n)
+	return 2 * (llTerm(o11, r1*c1/fn) + llTerm(o12, r1*c2/fn) +
+		llTerm(o21, r2*c1/fn) + llTerm(o22, r2*c2/fn))
+}
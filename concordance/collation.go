@@ -0,0 +1,157 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Collator compares strings according to some language-specific rules.
+// The returned value is negative if a < b, zero if a == b and
+// positive if a > b.
+type Collator interface {
+	Compare(a, b string) int
+}
+
+// CollatorForLocale returns a collator for a language code
+// (e.g. "cs", "cs_CZ", "en-US"). For languages without specific
+// rules, a collator comparing Unicode code points is returned.
+func CollatorForLocale(lang string) Collator {
+	lang = strings.ToLower(lang)
+	if lang == "cs" || strings.HasPrefix(lang, "cs_") || strings.HasPrefix(lang, "cs-") {
+		return czechCollator{}
+	}
+	return codepointCollator{}
+}
+
+// ------
+
+type codepointCollator struct{}
+
+func (c codepointCollator) Compare(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// ------
+
+// czechAlphabet lists primary letters of the Czech alphabet in their
+// order. Letters listed within a single item share a primary weight
+// and differ only on the secondary (diacritics) level.
+var czechAlphabet = []string{
+	"aá", "b", "c", "č", "dď", "eéě", "f", "g", "h", "ch", "ií", "j", "k",
+	"l", "m", "nň", "oó", "p", "q", "r", "ř", "s", "š", "tť", "uúů", "v",
+	"w", "x", "yý", "z", "ž",
+}
+
+const (
+	czechLetterBase  = 1000
+	czechChWeight    = czechLetterBase + 9
+	czechOtherOffset = 2000
+)
+
+type czechWeight struct {
+	primary   int
+	secondary int
+}
+
+var czechWeights = func() map[rune]czechWeight {
+	ans := make(map[rune]czechWeight)
+	for i, letters := range czechAlphabet {
+		if letters == "ch" {
+			continue
+		}
+		for j, r := range []rune(letters) {
+			ans[r] = czechWeight{primary: czechLetterBase + i, secondary: j}
+		}
+	}
+	return ans
+}()
+
+// czechCollator implements a simplified version of the Czech
+// collation (ČSN 97 6030) with three levels: letters ("ch" being
+// a single letter between "h" and "i"), diacritics and case
+// (lowercase first). Non-letters come before letters,
+// letters outside of the Czech alphabet come after them.
+type czechCollator struct{}
+
+type czechKey struct {
+	primary   []int
+	secondary []int
+	tertiary  []int
+}
+
+func (c czechCollator) key(s string) czechKey {
+	runes := []rune(s)
+	ans := czechKey{
+		primary:   make([]int, 0, len(runes)),
+		secondary: make([]int, 0, len(runes)),
+		tertiary:  make([]int, 0, len(runes)),
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		lr := unicode.ToLower(r)
+		caseW := 0
+		if lr != r {
+			caseW = 1
+		}
+		if lr == 'c' && i+1 < len(runes) && unicode.ToLower(runes[i+1]) == 'h' {
+			ans.primary = append(ans.primary, czechChWeight)
+			ans.secondary = append(ans.secondary, 0)
+			ans.tertiary = append(ans.tertiary, caseW)
+			i++
+			continue
+		}
+		w, ok := czechWeights[lr]
+		if !ok {
+			if unicode.IsLetter(lr) {
+				w = czechWeight{primary: czechOtherOffset + int(lr)}
+
+			} else {
+				// non-letters with high code points (e.g. „ or –) must
+				// not get mixed with letters; they share the last rank
+				// below letters and differ on the secondary level
+				w = czechWeight{primary: min(int(lr), czechLetterBase-1), secondary: int(lr)}
+			}
+		}
+		ans.primary = append(ans.primary, w.primary)
+		ans.secondary = append(ans.secondary, w.secondary)
+		ans.tertiary = append(ans.tertiary, caseW)
+	}
+	return ans
+}
+
+func compareInts(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
+
+func (c czechCollator) Compare(a, b string) int {
+	ka := c.key(a)
+	kb := c.key(b)
+	if v := compareInts(ka.primary, kb.primary); v != 0 {
+		return v
+	}
+	if v := compareInts(ka.secondary, kb.secondary); v != 0 {
+		return v
+	}
+	return compareInts(ka.tertiary, kb.tertiary)
+}
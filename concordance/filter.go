@@ -0,0 +1,225 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+type filterNode interface {
+	match(line *Line) bool
+	String() string
+}
+
+// ------
+
+type filterAnd struct {
+	left  filterNode
+	right filterNode
+}
+
+func (f *filterAnd) match(line *Line) bool {
+	return f.left.match(line) && f.right.match(line)
+}
+
+func (f *filterAnd) String() string {
+	return fmt.Sprintf("(%s and %s)", f.left, f.right)
+}
+
+// ------
+
+type filterOr struct {
+	left  filterNode
+	right filterNode
+}
+
+func (f *filterOr) match(line *Line) bool {
+	return f.left.match(line) || f.right.match(line)
+}
+
+func (f *filterOr) String() string {
+	return fmt.Sprintf("(%s or %s)", f.left, f.right)
+}
+
+// ------
+
+type filterNot struct {
+	arg filterNode
+}
+
+func (f *filterNot) match(line *Line) bool {
+	return !f.arg.match(line)
+}
+
+func (f *filterNot) String() string {
+	return fmt.Sprintf("not %s", f.arg)
+}
+
+// ------
+
+type filterTargetKind int
+
+const (
+	targetPosition filterTargetKind = iota
+	targetRange
+	targetProp
+)
+
+// filterTarget specifies which values a test is applied to
+type filterTarget struct {
+	kind  filterTargetKind
+	from  Position
+	to    Position
+	attr  string
+	label string
+}
+
+func (ft filterTarget) String() string {
+	if ft.kind == targetProp {
+		return ft.attr
+	}
+	return ft.label + "." + ft.attr
+}
+
+// values returns all the values the test should be applied to.
+// Positions outside of the line produce no values.
+func (ft filterTarget) values(line *Line) []string {
+	switch ft.kind {
+	case targetProp:
+		return []string{line.Props[ft.attr]}
+	case targetPosition:
+		if ft.from == PositionKWIC {
+			kwic := line.KWICTokens()
+			ans := make([]string, len(kwic))
+			for i, tok := range kwic {
+				ans[i] = tok.Attr(ft.attr)
+			}
+			return ans
+		}
+		if tok := line.TokenAt(ft.from); tok != nil {
+			return []string{tok.Attr(ft.attr)}
+		}
+	case targetRange:
+		ans := make([]string, 0, ft.to-ft.from+1)
+		for p := ft.from; p <= ft.to; p++ {
+			if tok := line.TokenAt(p); tok != nil {
+				ans = append(ans, tok.Attr(ft.attr))
+			}
+		}
+		return ans
+	}
+	return []string{}
+}
+
+// ------
+
+type filterTest struct {
+	target filterTarget
+	op     string
+	value  string
+	rx     *regexp.Regexp
+}
+
+func (f *filterTest) test(v string) bool {
+	switch f.op {
+	case "=":
+		return v == f.value
+	case "!=":
+		return v != f.value
+	case "~":
+		return f.rx.MatchString(v)
+	case "!~":
+		return !f.rx.MatchString(v)
+	}
+	return false
+}
+
+func (f *filterTest) match(line *Line) bool {
+	for _, v := range f.target.values(line) {
+		if f.test(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *filterTest) String() string {
+	return fmt.Sprintf("%s %s %s", f.target, f.op, strconv.Quote(f.value))
+}
+
+// ------
+
+// Filter is a compiled filter expression. The expression language
+// supports the following conditions:
+//
+//	kwic.<attr> <op> "<value>"       - any of the KWIC tokens
+//	<n>L.<attr> <op> "<value>"       - n-th token to the left of the KWIC
+//	<n>R.<attr> <op> "<value>"       - n-th token to the right of the KWIC
+//	left(<n>).<attr> <op> "<value>"  - any of n tokens to the left of the KWIC
+//	right(<n>).<attr> <op> "<value>" - any of n tokens to the right of the KWIC
+//	<struct>.<attr> <op> "<value>"   - a text property (Line.Props)
+//
+// Operators are `=`, `!=`, `~` (regexp search, i.e. use `^` and `$`
+// to anchor the pattern) and `!~`. Conditions can be combined using
+// `and` (`&&`), `or` (`||`), `not` (`!`) and parentheses.
+// Conditions on positions outside of the line are never satisfied.
+//
+// Example:
+//
+//	kwic.lemma = "dům" and left(3).tag ~ "^N" and not doc.txtype = "SCR: drama"
+type Filter struct {
+	root filterNode
+}
+
+// Match tests whether the line satisfies the filter
+func (f *Filter) Match(line *Line) bool {
+	return f.root.match(line)
+}
+
+// Apply returns a new slice with lines satisfying the filter
+func (f *Filter) Apply(lines []Line) []Line {
+	ans := make([]Line, 0, len(lines))
+	for i := range lines {
+		if f.root.match(&lines[i]) {
+			ans = append(ans, lines[i])
+		}
+	}
+	return ans
+}
+
+// String returns a normalized form of the expression
+// with explicit parentheses.
+func (f *Filter) String() string {
+	return f.root.String()
+}
+
+// ParseFilter compiles a filter expression. In case of a syntax
+// error, the returned error is of type *FilterSyntaxError.
+func ParseFilter(expr string) (*Filter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{expr: expr, tokens: tokens}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{root: root}, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLines() []Line {
+	return []Line{
+		mkLine(
+			"starý/starý/AAIS1 dům/dům/NNIS1 [stojí/stát/VB-S] u/u/RR--2 lesa/les/NNIS2",
+			map[string]string{"doc.txtype": "NOV: próza"},
+		),
+		mkLine(
+			"a/a/J^ [stál/stát/VpYS] dlouho/dlouho/Dg",
+			map[string]string{"doc.txtype": "SCR: drama"},
+		),
+		mkLine(
+			"[Stát/stát/NNIS1] rozhodl/rozhodnout/VpYS",
+			map[string]string{"doc.txtype": "NOV: próza"},
+		),
+	}
+}
+
+func filterLines(t *testing.T, expr string) []Line {
+	f, err := ParseFilter(expr)
+	assert.NoError(t, err)
+	if err != nil {
+		return nil
+	}
+	return f.Apply(testLines())
+}
+
+func TestFilterKWICAndLeftWindow(t *testing.T) {
+	ans := filterLines(t, `kwic.lemma = "stát" and left(3).tag ~ "^N"`)
+	assert.Len(t, ans, 1)
+	assert.Equal(t, "stojí", ans[0].TokenAt(PositionKWIC).Word)
+}
+
+func TestFilterExactPosition(t *testing.T) {
+	ans := filterLines(t, `1R.word = "dlouho" || 2R.lemma='les'`)
+	assert.Len(t, ans, 2)
+}
+
+func TestFilterProps(t *testing.T) {
+	ans := filterLines(t, `doc.txtype = "SCR: drama"`)
+	assert.Len(t, ans, 1)
+	assert.Equal(t, "stál", ans[0].TokenAt(PositionKWIC).Word)
+}
+
+func TestFilterNotAndGroups(t *testing.T) {
+	ans := filterLines(t, `not (doc.txtype = "SCR: drama" or kwic.word ~ "^[A-Z]")`)
+	assert.Len(t, ans, 1)
+	assert.Equal(t, "stojí", ans[0].TokenAt(PositionKWIC).Word)
+}
+
+func TestFilterOutOfLinePosition(t *testing.T) {
+	ans := filterLines(t, `1L.word != "x"`)
+	assert.Len(t, ans, 2)
+}
+
+func TestFilterPrecedence(t *testing.T) {
+	f, err := ParseFilter(`kwic.word = "a" or kwic.word = "b" and not 1L.tag ~ "N"`)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`(kwic.word = "a" or (kwic.word = "b" and not 1L.tag ~ "N"))`,
+		f.String(),
+	)
+}
+
+func TestFilterSyntaxErrors(t *testing.T) {
+	cases := map[string]int{
+		`kwic.lemma = stát`:           13,
+		`kwic.lemma = "stát`:          13,
+		`kwic.lemma "stát"`:           11,
+		`(kwic.lemma = "x"`:           17,
+		`kwic.lemma = "x")`:           16,
+		`left(x).tag = "N"`:           5,
+		`kwic.tag ~ "[N"`:             11,
+		`kwic.tag = "N" & 1L.x = "y"`: 15,
+		`kwic = "N"`:                  5,
+		``:                            0,
+	}
+	for expr, pos := range cases {
+		_, err := ParseFilter(expr)
+		if assert.Error(t, err, expr) {
+			synErr, ok := err.(*FilterSyntaxError)
+			assert.True(t, ok)
+			assert.Equal(t, pos, synErr.Pos, expr)
+		}
+	}
+}
+
+func TestFilterSyntaxErrorMessage(t *testing.T) {
+	_, err := ParseFilter(`kwic.lemma = stát`)
+	assert.EqualError(
+		t, err,
+		"filter syntax error at position 13: expected a quoted value after `=`, found `stát` (values must be enclosed in quotes)",
+	)
+	assert.Equal(t, "kwic.lemma = stát\n             ^", err.(*FilterSyntaxError).Context())
+}
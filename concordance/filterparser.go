@@ -0,0 +1,383 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FilterSyntaxError describes a problem found while parsing
+// a filter expression. Pos is a zero-based position (in runes)
+// within the expression.
+type FilterSyntaxError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("filter syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// Context returns the expression with a marker showing where
+// the error occurred. It is intended for user-facing messages.
+func (e *FilterSyntaxError) Context() string {
+	return e.Expr + "\n" + strings.Repeat(" ", e.Pos) + "^"
+}
+
+// ------
+
+type filterTokenType int
+
+const (
+	ftEOF filterTokenType = iota
+	ftIdent
+	ftString
+	ftDot
+	ftLParen
+	ftRParen
+	ftOp
+	ftAnd
+	ftOr
+	ftNot
+)
+
+func (ft filterTokenType) String() string {
+	switch ft {
+	case ftEOF:
+		return "end of expression"
+	case ftIdent:
+		return "identifier"
+	case ftString:
+		return "quoted string"
+	case ftDot:
+		return "`.`"
+	case ftLParen:
+		return "`(`"
+	case ftRParen:
+		return "`)`"
+	case ftOp:
+		return "operator"
+	case ftAnd:
+		return "`and`"
+	case ftOr:
+		return "`or`"
+	case ftNot:
+		return "`not`"
+	}
+	return "unknown"
+}
+
+type filterToken struct {
+	typ   filterTokenType
+	value string
+	pos   int
+}
+
+func (t filterToken) describe() string {
+	switch t.typ {
+	case ftIdent, ftOp:
+		return fmt.Sprintf("`%s`", t.value)
+	case ftString:
+		return fmt.Sprintf("\"%s\"", t.value)
+	}
+	return t.typ.String()
+}
+
+func isFilterIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func lexFilter(expr string) ([]filterToken, error) {
+	runes := []rune(expr)
+	ans := make([]filterToken, 0, len(runes)/2)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '.':
+			ans = append(ans, filterToken{typ: ftDot, value: ".", pos: i})
+			i++
+		case r == '(':
+			ans = append(ans, filterToken{typ: ftLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			ans = append(ans, filterToken{typ: ftRParen, value: ")", pos: i})
+			i++
+		case r == '=' || r == '~':
+			ans = append(ans, filterToken{typ: ftOp, value: string(r), pos: i})
+			i++
+		case r == '!':
+			if i+1 < len(runes) && (runes[i+1] == '=' || runes[i+1] == '~') {
+				ans = append(ans, filterToken{typ: ftOp, value: string(runes[i : i+2]), pos: i})
+				i += 2
+
+			} else {
+				ans = append(ans, filterToken{typ: ftNot, value: "!", pos: i})
+				i++
+			}
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, &FilterSyntaxError{
+					Expr: expr, Pos: i,
+					Msg: fmt.Sprintf("unexpected `%c` (did you mean `%c%c`?)", r, r, r),
+				}
+			}
+			typ := ftAnd
+			if r == '|' {
+				typ = ftOr
+			}
+			ans = append(ans, filterToken{typ: typ, value: string(runes[i : i+2]), pos: i})
+			i += 2
+		case r == '"' || r == '\'':
+			start := i
+			var val strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == r || runes[i+1] == '\\') {
+					i++
+				}
+				val.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, &FilterSyntaxError{Expr: expr, Pos: start, Msg: "unterminated string"}
+			}
+			i++
+			ans = append(ans, filterToken{typ: ftString, value: val.String(), pos: start})
+		case isFilterIdentRune(r):
+			start := i
+			for i < len(runes) && isFilterIdentRune(runes[i]) {
+				i++
+			}
+			val := string(runes[start:i])
+			typ := ftIdent
+			switch strings.ToLower(val) {
+			case "and":
+				typ = ftAnd
+			case "or":
+				typ = ftOr
+			case "not":
+				typ = ftNot
+			}
+			ans = append(ans, filterToken{typ: typ, value: val, pos: start})
+		default:
+			return nil, &FilterSyntaxError{
+				Expr: expr, Pos: i, Msg: fmt.Sprintf("unexpected character `%c`", r)}
+		}
+	}
+	ans = append(ans, filterToken{typ: ftEOF, pos: utf8.RuneCountInString(expr)})
+	return ans, nil
+}
+
+// ------
+
+type filterParser struct {
+	expr   string
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	ans := p.tokens[p.pos]
+	if ans.typ != ftEOF {
+		p.pos++
+	}
+	return ans
+}
+
+func (p *filterParser) errorf(tok filterToken, msg string, args ...any) error {
+	return &FilterSyntaxError{Expr: p.expr, Pos: tok.pos, Msg: fmt.Sprintf(msg, args...)}
+}
+
+func (p *filterParser) expect(typ filterTokenType, context string) (filterToken, error) {
+	tok := p.next()
+	if tok.typ != typ {
+		return tok, p.errorf(tok, "expected %s %s, found %s", typ, context, tok.describe())
+	}
+	return tok, nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().typ == ftOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().typ == ftAnd {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterNode, error) {
+	if p.peek().typ == ftNot {
+		p.next()
+		arg, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNot{arg: arg}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterNode, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case ftLParen:
+		p.next()
+		ans, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(ftRParen, "to close the group"); err != nil {
+			return nil, err
+		}
+		return ans, nil
+	case ftIdent:
+		return p.parseTest()
+	case ftEOF:
+		return nil, p.errorf(tok, "unexpected end of expression, expected a condition")
+	}
+	return nil, p.errorf(tok, "expected a condition or `(`, found %s", tok.describe())
+}
+
+// parseTarget parses one of:
+// kwic.<attr>, <n>L.<attr>, <n>R.<attr>, left(<n>).<attr>,
+// right(<n>).<attr>, <struct>.<attr> (a text property)
+func (p *filterParser) parseTarget() (filterTarget, error) {
+	first := p.next()
+	lname := strings.ToLower(first.value)
+	var ans filterTarget
+	if lname == "left" || lname == "right" {
+		if _, err := p.expect(ftLParen, fmt.Sprintf("after `%s`", first.value)); err != nil {
+			return ans, err
+		}
+		sizeTok, err := p.expect(ftIdent, "(window size)")
+		if err != nil {
+			return ans, err
+		}
+		size, err := strconv.Atoi(sizeTok.value)
+		if err != nil || size <= 0 {
+			return ans, p.errorf(sizeTok, "window size must be a positive integer, found `%s`", sizeTok.value)
+		}
+		if _, err := p.expect(ftRParen, "after window size"); err != nil {
+			return ans, err
+		}
+		ans.kind = targetRange
+		ans.from, ans.to = Position(-size), Position(-1)
+		if lname == "right" {
+			ans.from, ans.to = Position(1), Position(size)
+		}
+		ans.label = fmt.Sprintf("%s(%d)", lname, size)
+
+	} else {
+		ans.kind = targetPosition
+		pos, err := ParsePosition(first.value)
+		if err != nil {
+			ans.kind = targetProp
+		}
+		ans.from, ans.to = pos, pos
+		ans.label = first.value
+	}
+	if _, err := p.expect(ftDot, fmt.Sprintf("after `%s`", ans.label)); err != nil {
+		return ans, err
+	}
+	attrTok, err := p.expect(ftIdent, "(attribute name)")
+	if err != nil {
+		return ans, err
+	}
+	ans.attr = attrTok.value
+	if ans.kind == targetProp {
+		ans.attr = first.value + "." + attrTok.value
+		ans.label = ""
+	}
+	return ans, nil
+}
+
+func (p *filterParser) parseTest() (filterNode, error) {
+	target, err := p.parseTarget()
+	if err != nil {
+		return nil, err
+	}
+	opTok := p.next()
+	if opTok.typ != ftOp {
+		return nil, p.errorf(
+			opTok, "expected one of `=`, `!=`, `~`, `!~` after `%s`, found %s",
+			target, opTok.describe())
+	}
+	valTok := p.next()
+	if valTok.typ != ftString {
+		msg := "expected a quoted value after `%s`, found %s"
+		if valTok.typ == ftIdent {
+			msg += " (values must be enclosed in quotes)"
+		}
+		return nil, p.errorf(valTok, msg, opTok.value, valTok.describe())
+	}
+	ans := &filterTest{target: target, op: opTok.value, value: valTok.value}
+	if opTok.value == "~" || opTok.value == "!~" {
+		ans.rx, err = regexp.Compile(valTok.value)
+		if err != nil {
+			return nil, p.errorf(valTok, "invalid regular expression: %s", err)
+		}
+	}
+	return ans, nil
+}
+
+func (p *filterParser) parse() (filterNode, error) {
+	ans, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != ftEOF {
+		if tok.typ == ftRParen {
+			return nil, p.errorf(tok, "unmatched `)`")
+		}
+		return nil, p.errorf(tok, "expected `and`, `or` or end of expression, found %s", tok.describe())
+	}
+	return ans, nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FreqCriterion specifies how concordance lines are grouped
+// when calculating a frequency distribution.
+type FreqCriterion struct {
+
+	// Attrs is a list of positional attributes whose values
+	// (separated by spaces) form an item of the distribution.
+	// It is ignored in case Prop is set.
+	Attrs []string `json:"attrs,omitempty"`
+
+	// Position is a position relative to the KWIC. For PositionKWIC,
+	// values of all the KWIC tokens are used.
+	Position Position `json:"position"`
+
+	// Prop is a text property (e.g. doc.txtype) the lines
+	// are grouped by.
+	Prop string `json:"prop,omitempty"`
+
+	// IgnoreCase merges values differing only in case
+	// (the lowercase variant is reported).
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+}
+
+// ParseFreqCriterion parses a frequency criterion specification:
+//
+// `<attr>[,<attr>...][/i] <position>` for positional attributes (e.g. "lemma,tag/i 1L")
+//
+// `prop:<struct.attr>` for text properties (e.g. "prop:doc.txtype")
+func ParseFreqCriterion(spec string) (FreqCriterion, error) {
+	var ans FreqCriterion
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "prop:") {
+		ans.Prop = strings.TrimPrefix(spec, "prop:")
+		if ans.Prop == "" {
+			return ans, fmt.Errorf("invalid freq. criterion `%s`: missing property", spec)
+		}
+		return ans, nil
+	}
+	attrPart, posPart, ok := strings.Cut(spec, " ")
+	if !ok {
+		posPart = "KWIC"
+	}
+	attrs, flags, _ := strings.Cut(attrPart, "/")
+	for _, f := range flags {
+		if f != 'i' {
+			return ans, fmt.Errorf("invalid freq. criterion `%s`: unknown flag `%c`", spec, f)
+		}
+		ans.IgnoreCase = true
+	}
+	for _, a := range strings.Split(attrs, ",") {
+		if a == "" {
+			return ans, fmt.Errorf("invalid freq. criterion `%s`: empty attribute", spec)
+		}
+		ans.Attrs = append(ans.Attrs, a)
+	}
+	pos, err := ParsePosition(posPart)
+	if err != nil {
+		return ans, fmt.Errorf("invalid freq. criterion `%s`: %w", spec, err)
+	}
+	ans.Position = pos
+	return ans, nil
+}
+
+// value returns a value the line is counted under. The second returned
+// value is false in case the line cannot be counted (e.g. the position
+// is outside of the line).
+func (fc FreqCriterion) value(line *Line) (string, bool) {
+	var ans string
+	if fc.Prop != "" {
+		ans = line.Props[fc.Prop]
+		if ans == "" {
+			return "", false
+		}
+
+	} else {
+		var tokens []*Token
+		if fc.Position == PositionKWIC {
+			tokens = line.KWICTokens()
+
+		} else if tok := line.TokenAt(fc.Position); tok != nil {
+			tokens = []*Token{tok}
+		}
+		if len(tokens) == 0 {
+			return "", false
+		}
+		values := make([]string, 0, len(tokens)*len(fc.Attrs))
+		for _, tok := range tokens {
+			for _, attr := range fc.Attrs {
+				values = append(values, tok.Attr(attr))
+			}
+		}
+		ans = strings.Join(values, " ")
+	}
+	if fc.IgnoreCase {
+		ans = strings.ToLower(ans)
+	}
+	return ans, true
+}
+
+// ------
+
+// FreqDistribItem is a single item of a frequency distribution
+type FreqDistribItem struct {
+	Word string `json:"word"`
+	Freq int64  `json:"freq"`
+
+	// Norm is a size of the base the IPM is calculated from.
+	// By default, this is the number of lines the distribution
+	// has been calculated from.
+	Norm int64   `json:"norm"`
+	IPM  float32 `json:"ipm"`
+
+	// Rel is a relative frequency within the processed lines
+	Rel float64 `json:"rel"`
+}
+
+func (item *FreqDistribItem) updateIPM() {
+	if item.Norm > 0 {
+		item.IPM = float32(float64(item.Freq) / float64(item.Norm) * 1e6)
+
+	} else {
+		item.IPM = 0
+	}
+}
+
+// FreqSortField specifies an ordering of a frequency distribution
+type FreqSortField string
+
+const (
+	FreqSortByFreq FreqSortField = "freq"
+	FreqSortByIPM  FreqSortField = "ipm"
+	FreqSortByWord FreqSortField = "word"
+)
+
+func (f FreqSortField) Validate() error {
+	if f == FreqSortByFreq || f == FreqSortByIPM || f == FreqSortByWord {
+		return nil
+	}
+	return fmt.Errorf("invalid freq. sort field: %s", f)
+}
+
+// FreqDistrib is a frequency distribution calculated from
+// concordance lines.
+type FreqDistrib struct {
+
+	// ConcSize is the number of lines the distribution has been
+	// calculated from
+	ConcSize int                `json:"concSize"`
+	Freqs    []*FreqDistribItem `json:"freqs"`
+}
+
+// ApplyNorms sets custom norms for individual items (typically sizes
+// of text types in tokens when calculating distribution of Props values)
+// and recalculates IPM values. Items not found in `norms` are not
+// changed.
+func (fd *FreqDistrib) ApplyNorms(norms map[string]int64) {
+	for _, item := range fd.Freqs {
+		if n, ok := norms[item.Word]; ok {
+			item.Norm = n
+			item.updateIPM()
+		}
+	}
+}
+
+// Sort sorts the items by the specified field. Frequencies
+// are sorted in descending order, words in ascending one using
+// a collator for the provided language (see CollatorForLocale).
+// Ties are always resolved by words.
+func (fd *FreqDistrib) Sort(by FreqSortField, lang string) {
+	coll := CollatorForLocale(lang)
+	sort.SliceStable(fd.Freqs, func(i, j int) bool {
+		a, b := fd.Freqs[i], fd.Freqs[j]
+		switch by {
+		case FreqSortByFreq:
+			if a.Freq != b.Freq {
+				return a.Freq > b.Freq
+			}
+		case FreqSortByIPM:
+			if a.IPM != b.IPM {
+				return a.IPM > b.IPM
+			}
+		}
+		return coll.Compare(a.Word, b.Word) < 0
+	})
+}
+
+// Truncate keeps at most `maxItems` first items
+func (fd *FreqDistrib) Truncate(maxItems int) {
+	if maxItems >= 0 && len(fd.Freqs) > maxItems {
+		fd.Freqs = fd.Freqs[:maxItems]
+	}
+}
+
+// CalcFreqs calculates a frequency distribution of lines grouped
+// by the provided criterion. Lines where the criterion cannot be
+// applied (missing token at the position, empty property) are not
+// counted but they are still included in the ConcSize.
+// The result is sorted by frequencies.
+func CalcFreqs(lines []Line, crit FreqCriterion) *FreqDistrib {
+	counts := make(map[string]*FreqDistribItem)
+	ans := &FreqDistrib{
+		ConcSize: len(lines),
+		Freqs:    make([]*FreqDistribItem, 0, len(lines)/2),
+	}
+	for i := range lines {
+		v, ok := crit.value(&lines[i])
+		if !ok {
+			continue
+		}
+		item, ok := counts[v]
+		if !ok {
+			item = &FreqDistribItem{Word: v}
+			counts[v] = item
+			ans.Freqs = append(ans.Freqs, item)
+		}
+		item.Freq++
+	}
+	for _, item := range ans.Freqs {
+		item.Norm = int64(len(lines))
+		item.Rel = float64(item.Freq) / float64(len(lines))
+		item.updateIPM()
+	}
+	ans.Sort(FreqSortByFreq, "")
+	return ans
+}
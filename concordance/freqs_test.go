@@ -0,0 +1,77 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcFreqsKWICLemma(t *testing.T) {
+	fd := CalcFreqs(testLines(), FreqCriterion{Attrs: []string{"lemma"}})
+	assert.Equal(t, 3, fd.ConcSize)
+	assert.Len(t, fd.Freqs, 1)
+	assert.Equal(t, "stát", fd.Freqs[0].Word)
+	assert.Equal(t, int64(3), fd.Freqs[0].Freq)
+	assert.Equal(t, 1.0, fd.Freqs[0].Rel)
+}
+
+func TestCalcFreqsMultiAttr(t *testing.T) {
+	crit, err := ParseFreqCriterion("word,tag/i")
+	assert.NoError(t, err)
+	fd := CalcFreqs(testLines(), crit)
+	assert.Len(t, fd.Freqs, 3)
+	fd.Sort(FreqSortByWord, "cs")
+	assert.Equal(t, "stál vpys", fd.Freqs[0].Word)
+	assert.Equal(t, "stát nnis1", fd.Freqs[1].Word)
+	assert.Equal(t, "stojí vb-s", fd.Freqs[2].Word)
+}
+
+func TestCalcFreqsPositionSkipsMissing(t *testing.T) {
+	crit, err := ParseFreqCriterion("tag 1L")
+	assert.NoError(t, err)
+	fd := CalcFreqs(testLines(), crit)
+	assert.Equal(t, 3, fd.ConcSize)
+	assert.Len(t, fd.Freqs, 2)
+	assert.InDelta(t, 1.0/3.0, fd.Freqs[0].Rel, 0.0001)
+}
+
+func TestCalcFreqsProps(t *testing.T) {
+	crit, err := ParseFreqCriterion("prop:doc.txtype")
+	assert.NoError(t, err)
+	fd := CalcFreqs(testLines(), crit)
+	assert.Equal(t, "NOV: próza", fd.Freqs[0].Word)
+	assert.Equal(t, int64(2), fd.Freqs[0].Freq)
+	assert.Equal(t, "SCR: drama", fd.Freqs[1].Word)
+
+	fd.ApplyNorms(map[string]int64{"NOV: próza": 2000000, "SCR: drama": 100000})
+	fd.Sort(FreqSortByIPM, "")
+	assert.Equal(t, "SCR: drama", fd.Freqs[0].Word)
+	assert.Equal(t, float32(10), fd.Freqs[0].IPM)
+	assert.Equal(t, float32(1), fd.Freqs[1].IPM)
+
+	fd.Truncate(1)
+	assert.Len(t, fd.Freqs, 1)
+}
+
+func TestParseFreqCriterionErrors(t *testing.T) {
+	for _, spec := range []string{"prop:", "lemma,/i", "lemma/x", "lemma 0X"} {
+		_, err := ParseFreqCriterion(spec)
+		assert.Error(t, err, spec)
+	}
+}
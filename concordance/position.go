@@ -0,0 +1,146 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Position is a token position relative to the KWIC.
+// Zero value is the KWIC itself, negative values are positions
+// to the left of the KWIC's first token and positive values
+// are positions to the right of the KWIC's last token
+// (i.e. for a multi-token KWIC, "1R" is the first token
+// after the whole KWIC).
+type Position int
+
+const (
+	PositionKWIC Position = 0
+)
+
+// ParsePosition parses KonText-like position notation:
+// "KWIC" (or "0"), "<n>L" for positions to the left and "<n>R"
+// for positions to the right of the KWIC.
+func ParsePosition(s string) (Position, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "KWIC" || s == "0" {
+		return PositionKWIC, nil
+	}
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid position: `%s`", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid position: `%s`", s)
+	}
+	switch s[len(s)-1] {
+	case 'L':
+		return Position(-n), nil
+	case 'R':
+		return Position(n), nil
+	}
+	return 0, fmt.Errorf("invalid position: `%s`", s)
+}
+
+func (p Position) String() string {
+	if p < 0 {
+		return fmt.Sprintf("%dL", -p)
+	}
+	if p > 0 {
+		return fmt.Sprintf("%dR", p)
+	}
+	return "KWIC"
+}
+
+// ------
+
+// kwicRange returns indices of the first and the last KWIC token
+// within the provided tokens. In case there is no KWIC, -1, -1
+// is returned.
+func kwicRange(tokens []*Token) (int, int) {
+	start, end := -1, -1
+	for i, tok := range tokens {
+		if tok.MatchType == MatchTypeKWIC {
+			if start == -1 {
+				start = i
+			}
+			end = i
+
+		} else if start > -1 {
+			break
+		}
+	}
+	return start, end
+}
+
+// KWICTokens returns all the tokens forming the KWIC.
+// In case there is no KWIC in the line, nil is returned.
+func (line *Line) KWICTokens() []*Token {
+	tokens := line.Text.Tokens()
+	start, end := kwicRange(tokens)
+	if start == -1 {
+		return nil
+	}
+	return tokens[start : end+1]
+}
+
+// TokenAt returns a token at the specified position relative
+// to the KWIC. For PositionKWIC, the first KWIC token is returned.
+// In case the position is out of the line or there is no KWIC
+// in the line, nil is returned.
+func (line *Line) TokenAt(pos Position) *Token {
+	tokens := line.Text.Tokens()
+	start, end := kwicRange(tokens)
+	if start == -1 {
+		return nil
+	}
+	idx := start
+	if pos < 0 {
+		idx = start + int(pos)
+
+	} else if pos > 0 {
+		idx = end + int(pos)
+	}
+	if idx < 0 || idx >= len(tokens) {
+		return nil
+	}
+	return tokens[idx]
+}
+
+// LeftContext returns up to `size` tokens preceding the KWIC,
+// ordered as in the text. A negative size is treated as zero.
+func (line *Line) LeftContext(size int) []*Token {
+	tokens := line.Text.Tokens()
+	start, _ := kwicRange(tokens)
+	if start == -1 {
+		return nil
+	}
+	return tokens[max(0, start-max(0, size)):start]
+}
+
+// RightContext returns up to `size` tokens following the KWIC.
+// A negative size is treated as zero.
+func (line *Line) RightContext(size int) []*Token {
+	tokens := line.Text.Tokens()
+	_, end := kwicRange(tokens)
+	if end == -1 {
+		return nil
+	}
+	return tokens[end+1 : min(len(tokens), end+1+max(0, size))]
+}
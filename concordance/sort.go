@@ -0,0 +1,220 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortKey specifies a single level of concordance sorting.
+type SortKey struct {
+
+	// Attr is a positional attribute (word, lemma, tag,...)
+	// the lines are sorted by. It is ignored in case Prop is set.
+	Attr string `json:"attr,omitempty"`
+
+	// Position is a position relative to the KWIC. For PositionKWIC,
+	// values of all the KWIC tokens are used (separated by spaces).
+	Position Position `json:"position"`
+
+	// Prop is a text property (e.g. doc.pubyear) the lines are sorted by.
+	Prop string `json:"prop,omitempty"`
+
+	// IgnoreCase makes the comparison case-insensitive
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+
+	// ATergo sorts by values read backwards (i.e. by word endings)
+	ATergo bool `json:"aTergo,omitempty"`
+
+	// Numeric compares values as numbers. Values which cannot
+	// be parsed are placed after the numeric ones (regardless
+	// of Descending) and they are sorted alphabetically.
+	Numeric bool `json:"numeric,omitempty"`
+
+	// Descending reverses the order of the level. For numeric
+	// keys, only the order of numeric values is reversed.
+	Descending bool `json:"descending,omitempty"`
+}
+
+// ParseSortKey parses a KonText-like sorting specification:
+//
+// `<attr>[/<flags>] <position>` for positional attributes (e.g. "lemma/i 1L")
+//
+// `prop:<struct.attr>[/<flags>]` for text properties (e.g. "prop:doc.pubyear/nd")
+//
+// Flags are: i (ignore case), r (a tergo), n (numeric), d (descending)
+func ParseSortKey(spec string) (SortKey, error) {
+	var ans SortKey
+	spec = strings.TrimSpace(spec)
+	var attrPart, posPart string
+	if strings.HasPrefix(spec, "prop:") {
+		attrPart = strings.TrimPrefix(spec, "prop:")
+
+	} else {
+		var ok bool
+		attrPart, posPart, ok = strings.Cut(spec, " ")
+		if !ok {
+			posPart = "KWIC"
+		}
+	}
+	name, flags, _ := strings.Cut(attrPart, "/")
+	if name == "" {
+		return ans, fmt.Errorf("invalid sort key `%s`: missing attribute", spec)
+	}
+	for _, f := range flags {
+		switch f {
+		case 'i':
+			ans.IgnoreCase = true
+		case 'r':
+			ans.ATergo = true
+		case 'n':
+			ans.Numeric = true
+		case 'd':
+			ans.Descending = true
+		default:
+			return ans, fmt.Errorf("invalid sort key `%s`: unknown flag `%c`", spec, f)
+		}
+	}
+	if strings.HasPrefix(spec, "prop:") {
+		ans.Prop = name
+		return ans, nil
+	}
+	ans.Attr = name
+	pos, err := ParsePosition(posPart)
+	if err != nil {
+		return ans, fmt.Errorf("invalid sort key `%s`: %w", spec, err)
+	}
+	ans.Position = pos
+	return ans, nil
+}
+
+func (sk SortKey) value(line *Line) string {
+	var ans string
+	if sk.Prop != "" {
+		ans = line.Props[sk.Prop]
+
+	} else if sk.Position == PositionKWIC {
+		kwic := line.KWICTokens()
+		values := make([]string, len(kwic))
+		for i, tok := range kwic {
+			values[i] = tok.Attr(sk.Attr)
+		}
+		ans = strings.Join(values, " ")
+
+	} else if tok := line.TokenAt(sk.Position); tok != nil {
+		ans = tok.Attr(sk.Attr)
+	}
+	if sk.IgnoreCase {
+		ans = strings.ToLower(ans)
+	}
+	if sk.ATergo {
+		runes := []rune(ans)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		ans = string(runes)
+	}
+	return ans
+}
+
+func (sk SortKey) compare(a, b string, coll Collator) int {
+	if !sk.Numeric {
+		ans := coll.Compare(a, b)
+		if sk.Descending {
+			return -ans
+		}
+		return ans
+	}
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	switch {
+	case errA == nil && errB == nil:
+		var ans int
+		if fa < fb {
+			ans = -1
+
+		} else if fa > fb {
+			ans = 1
+		}
+		if sk.Descending {
+			return -ans
+		}
+		return ans
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return coll.Compare(a, b)
+}
+
+// ------
+
+// LineSorter sorts concordance lines by one or more keys.
+type LineSorter struct {
+	keys     []SortKey
+	collator Collator
+}
+
+// Sort sorts lines in place. The sorting is stable so lines
+// with equal keys keep their original order.
+func (ls *LineSorter) Sort(lines []Line) {
+	values := make([][]string, len(lines))
+	for i := range lines {
+		values[i] = make([]string, len(ls.keys))
+		for j, k := range ls.keys {
+			values[i][j] = k.value(&lines[i])
+		}
+	}
+	idxs := make([]int, len(lines))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		va := values[idxs[i]]
+		vb := values[idxs[j]]
+		for k, key := range ls.keys {
+			if c := key.compare(va[k], vb[k], ls.collator); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	sorted := make([]Line, len(lines))
+	for i, idx := range idxs {
+		sorted[i] = lines[idx]
+	}
+	copy(lines, sorted)
+}
+
+// NewLineSorter creates a sorter for provided keys (applied
+// in the order of their appearance). The `lang` argument
+// selects collation rules (see CollatorForLocale).
+func NewLineSorter(lang string, keys ...SortKey) *LineSorter {
+	return &LineSorter{
+		keys:     keys,
+		collator: CollatorForLocale(lang),
+	}
+}
+
+// SortLines is a shortcut for NewLineSorter(lang, keys...).Sort(lines)
+func SortLines(lines []Line, lang string, keys ...SortKey) {
+	NewLineSorter(lang, keys...).Sort(lines)
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concordance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mkLine creates a line from `word/lemma/tag` items separated by spaces.
+// KWIC tokens are enclosed in square brackets.
+func mkLine(src string, props map[string]string) Line {
+	var ans Line
+	for _, item := range strings.Fields(src) {
+		var tok Token
+		if strings.HasPrefix(item, "[") && strings.HasSuffix(item, "]") {
+			item = item[1 : len(item)-1]
+			tok.MatchType = MatchTypeKWIC
+		}
+		values := strings.Split(item, "/")
+		tok.Word = values[0]
+		tok.Attrs = map[string]string{"lemma": values[0], "tag": ""}
+		if len(values) > 1 {
+			tok.Attrs["lemma"] = values[1]
+		}
+		if len(values) > 2 {
+			tok.Attrs["tag"] = values[2]
+		}
+		ans.Text = append(ans.Text, &tok)
+	}
+	ans.Props = props
+	return ans
+}
+
+func kwicWords(lines []Line) []string {
+	ans := make([]string, len(lines))
+	for i, line := range lines {
+		ans[i] = line.TokenAt(PositionKWIC).Word
+	}
+	return ans
+}
+
+func TestParsePosition(t *testing.T) {
+	for spec, exp := range map[string]Position{"KWIC": 0, "0": 0, "1L": -1, "3r": 3} {
+		pos, err := ParsePosition(spec)
+		assert.NoError(t, err)
+		assert.Equal(t, exp, pos)
+	}
+	for _, spec := range []string{"", "L", "0L", "-1R", "2X"} {
+		_, err := ParsePosition(spec)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, "2L", Position(-2).String())
+}
+
+func TestTokenAtMultiTokenKWIC(t *testing.T) {
+	line := mkLine("a b [c] [d] e", nil)
+	assert.Equal(t, "b", line.TokenAt(-1).Word)
+	assert.Equal(t, "c", line.TokenAt(PositionKWIC).Word)
+	assert.Equal(t, "e", line.TokenAt(1).Word)
+	assert.Nil(t, line.TokenAt(2))
+	assert.Nil(t, line.TokenAt(-3))
+	assert.Len(t, line.KWICTokens(), 2)
+}
+
+func TestSortByLeftContext(t *testing.T) {
+	lines := []Line{
+		mkLine("x [a] y", nil),
+		mkLine("w [b] y", nil),
+		mkLine("v [c] y", nil),
+	}
+	SortLines(lines, "en", SortKey{Attr: "word", Position: -1})
+	assert.Equal(t, []string{"c", "b", "a"}, kwicWords(lines))
+}
+
+func TestSortMultiLevel(t *testing.T) {
+	lines := []Line{
+		mkLine("[a] z", nil),
+		mkLine("[b] x", nil),
+		mkLine("[a] y", nil),
+	}
+	SortLines(
+		lines, "en",
+		SortKey{Attr: "word", Position: PositionKWIC, Descending: true},
+		SortKey{Attr: "word", Position: 1},
+	)
+	assert.Equal(t, "x", lines[0].TokenAt(1).Word)
+	assert.Equal(t, "y", lines[1].TokenAt(1).Word)
+	assert.Equal(t, "z", lines[2].TokenAt(1).Word)
+}
+
+func TestSortCzechCollation(t *testing.T) {
+	lines := []Line{
+		mkLine("[chata]", nil),
+		mkLine("[hrad]", nil),
+		mkLine("[ilustrace]", nil),
+		mkLine("[čert]", nil),
+		mkLine("[cesta]", nil),
+		mkLine("[Dům]", nil),
+	}
+	SortLines(lines, "cs", SortKey{Attr: "word", IgnoreCase: true})
+	assert.Equal(
+		t,
+		[]string{"cesta", "čert", "Dům", "hrad", "chata", "ilustrace"},
+		kwicWords(lines),
+	)
+}
+
+func TestCzechCollatorLevels(t *testing.T) {
+	c := CollatorForLocale("cs_CZ")
+	assert.Less(t, c.Compare("kačer", "kachna"), 0)
+	assert.Less(t, c.Compare("dél", "dělo"), 0)
+	assert.Less(t, c.Compare("pes", "Pes"), 0)
+	assert.Less(t, c.Compare("ďas", "dbát"), 0)
+	assert.Equal(t, 0, c.Compare("chata", "chata"))
+}
+
+func TestCzechCollatorPunctuation(t *testing.T) {
+	c := CollatorForLocale("cs")
+	for _, punct := range []string{"„", "–", "“", "€", ",", "0"} {
+		assert.Less(t, c.Compare(punct, "a"), 0, punct)
+		assert.Less(t, c.Compare(punct, "ž"), 0, punct)
+		assert.Less(t, c.Compare(punct, "ß"), 0, punct)
+		assert.Less(t, c.Compare("a"+punct, "aa"), 0, punct)
+	}
+	assert.Less(t, c.Compare("–", "„"), 0)
+	assert.NotEqual(t, 0, c.Compare("„", "“"))
+	lines := []Line{
+		mkLine("[žena]", nil),
+		mkLine("[„]", nil),
+		mkLine("[ano]", nil),
+		mkLine("[–]", nil),
+	}
+	SortLines(lines, "cs", SortKey{Attr: "word"})
+	assert.Equal(t, []string{"–", "„", "ano", "žena"}, kwicWords(lines))
+}
+
+func TestSortATergo(t *testing.T) {
+	lines := []Line{
+		mkLine("[domem]", nil),
+		mkLine("[hradu]", nil),
+		mkLine("[lesa]", nil),
+	}
+	SortLines(lines, "", SortKey{Attr: "word", ATergo: true})
+	assert.Equal(t, []string{"lesa", "domem", "hradu"}, kwicWords(lines))
+}
+
+func TestSortByNumericProp(t *testing.T) {
+	lines := []Line{
+		mkLine("[a]", map[string]string{"doc.pubyear": "2001"}),
+		mkLine("[b]", map[string]string{"doc.pubyear": "unknown"}),
+		mkLine("[c]", map[string]string{"doc.pubyear": "998"}),
+	}
+	key, err := ParseSortKey("prop:doc.pubyear/n")
+	assert.NoError(t, err)
+	SortLines(lines, "", key)
+	assert.Equal(t, []string{"c", "a", "b"}, kwicWords(lines))
+}
+
+func TestSortByNumericPropDescending(t *testing.T) {
+	lines := []Line{
+		mkLine("[a]", map[string]string{"doc.pubyear": "2001"}),
+		mkLine("[b]", map[string]string{"doc.pubyear": "unknown"}),
+		mkLine("[c]", map[string]string{"doc.pubyear": "998"}),
+		mkLine("[d]", map[string]string{"doc.pubyear": "n/a"}),
+		mkLine("[e]", map[string]string{"doc.pubyear": "2024"}),
+	}
+	key, err := ParseSortKey("prop:doc.pubyear/nd")
+	assert.NoError(t, err)
+	SortLines(lines, "en", key)
+	assert.Equal(t, []string{"e", "a", "c", "d", "b"}, kwicWords(lines))
+}
+
+func TestContextNegativeSize(t *testing.T) {
+	line := mkLine("a b [c] d e", nil)
+	assert.Empty(t, line.LeftContext(-3))
+	assert.Empty(t, line.RightContext(-1))
+	assert.Len(t, line.LeftContext(5), 2)
+	assert.Len(t, line.RightContext(1), 1)
+}
+
+func TestParseSortKey(t *testing.T) {
+	key, err := ParseSortKey("lemma/ir 2L")
+	assert.NoError(t, err)
+	assert.Equal(t, SortKey{Attr: "lemma", Position: -2, IgnoreCase: true, ATergo: true}, key)
+	key, err = ParseSortKey("word")
+	assert.NoError(t, err)
+	assert.Equal(t, PositionKWIC, key.Position)
+	_, err = ParseSortKey("word/x")
+	assert.Error(t, err)
+	_, err = ParseSortKey("word 2Q")
+	assert.Error(t, err)
+}
@@ -51,6 +51,20 @@ const (
 // ------------- token and methods
 // -------------------------------
 
+// TokenFeature is a decoded morphological feature of a token
+// (e.g. a single position of a positional tag).
+type TokenFeature struct {
+
+	// Label is a localized name of the feature
+	Label string `json:"label"`
+
+	// Value is the raw value as found in the tag
+	Value string `json:"value"`
+
+	// ValueLabel is a localized description of the value
+	ValueLabel string `json:"valueLabel,omitempty"`
+}
+
 // Token is a single text position in a corpus text.
 type Token struct {
 	Word string `json:"word"`
@@ -66,6 +80,11 @@ type Token struct {
 	// of a respective position.
 	Attrs map[string]string `json:"attrs"`
 
+	// Features contains decoded morphological features of the token
+	// identified by feature IDs (e.g. "case"). The property is filled
+	// only on demand (see tagset.Enricher).
+	Features map[string]TokenFeature `json:"features,omitempty"`
+
 	// ErrMsg is an error message in case problems occured
 	// with parsing related to the token. The policy here is
 	// to always return a token with value replaced by a placeholder
@@ -78,12 +97,13 @@ func (t *Token) HasError() bool {
 }
 
 type tokenJson struct {
-	Type      string            `json:"type"`
-	Word      string            `json:"word"`
-	Strong    bool              `json:"strong"`
-	MatchType MatchType         `json:"matchType,omitempty"`
-	Attrs     map[string]string `json:"attrs"`
-	ErrMsg    string            `json:"errMsg,omitempty"`
+	Type      string                  `json:"type"`
+	Word      string                  `json:"word"`
+	Strong    bool                    `json:"strong"`
+	MatchType MatchType               `json:"matchType,omitempty"`
+	Attrs     map[string]string       `json:"attrs"`
+	Features  map[string]TokenFeature `json:"features,omitempty"`
+	ErrMsg    string                  `json:"errMsg,omitempty"`
 }
 
 func (t *Token) MarshalJSON() ([]byte, error) {
@@ -94,6 +114,7 @@ func (t *Token) MarshalJSON() ([]byte, error) {
 			Strong:    t.Strong,
 			MatchType: t.MatchType,
 			Attrs:     t.Attrs,
+			Features:  t.Features,
 			ErrMsg:    t.ErrMsg,
 		},
 	)
@@ -108,6 +129,7 @@ func (t *Token) UnmarshalJSON(data []byte) error {
 	t.Strong = tmp.Strong
 	t.MatchType = tmp.MatchType
 	t.Attrs = tmp.Attrs
+	t.Features = tmp.Features
 	t.ErrMsg = tmp.ErrMsg
 	return nil
 }
@@ -116,6 +138,16 @@ func (t *Token) String() string {
 	return t.Word
 }
 
+// Attr returns a value of a positional attribute. The "word"
+// attribute is mapped to the Word property, all the other ones
+// are searched in Attrs.
+func (t *Token) Attr(name string) string {
+	if name == "word" {
+		return t.Word
+	}
+	return t.Attrs[name]
+}
+
 // ----------------------------------------------
 
 // TokenSlice represents a flow of tokens and markup
@@ -0,0 +1,149 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// placeholderRegexp matches `${n}` placeholders in dynamic setup templates
+var placeholderRegexp = regexp.MustCompile(`\$\{(\d+)\}`)
+
+// idPatternRegexp converts a dynamic corpus ID into a regular
+// expression with one capturing group for each `*`
+func idPatternRegexp(id string) *regexp.Regexp {
+	parts := strings.Split(id, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "(.*?)") + "$")
+}
+
+// idSpecificity returns the number of literal characters
+// and the number of wildcards of a dynamic corpus ID
+func idSpecificity(id string) (int, int) {
+	wildcards := strings.Count(id, "*")
+	return utf8.RuneCountInString(id) - wildcards, wildcards
+}
+
+// expandPlaceholders replaces `${n}` placeholders by respective
+// items of `parts`. Placeholders with an invalid index and all
+// the other text (including `$` signs) are kept untouched.
+func expandPlaceholders(tpl string, parts []string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(tpl, func(ph string) string {
+		idx, err := strconv.Atoi(ph[2 : len(ph)-1])
+		if err != nil || idx >= len(parts) {
+			return ph
+		}
+		return parts[idx]
+	})
+}
+
+// MatchID tests whether a concrete corpus name matches the setup ID.
+// For dynamic setups (see IsDynamic), the ID is used as a glob pattern
+// where `*` matches any sequence of characters. The returned slice
+// contains the matched name followed by parts captured by individual
+// wildcards. For non-dynamic setups, the name must be equal to the ID.
+func (cs *CorpusSetup) MatchID(name string) ([]string, bool) {
+	if !cs.IsDynamic() {
+		if name == cs.ID {
+			return []string{name}, true
+		}
+		return nil, false
+	}
+	ans := idPatternRegexp(cs.ID).FindStringSubmatch(name)
+	return ans, ans != nil
+}
+
+// Concrete creates a setup for a concrete corpus name matching the
+// dynamic setup ID (see MatchID). The ID is replaced by the name and
+// values of FullName and Description are treated as templates where
+// `${1}`, `${2}`,... are replaced by parts captured by the respective
+// wildcards and `${0}` by the whole name. No other syntax is recognized
+// (i.e. `$1` or a plain `$` are kept as they are). Other values are copied
+// from the original setup (see CorpusSetup.Extend) so the result can be
+// modified safely. Only dynamic setups (see IsDynamic) are accepted.
+func (cs *CorpusSetup) Concrete(name string) (*CorpusSetup, error) {
+	if !cs.IsDynamic() {
+		return nil, fmt.Errorf("corpus setup %s is not dynamic", cs.ID)
+	}
+	parts, ok := cs.MatchID(name)
+	if !ok {
+		return nil, fmt.Errorf("corpus %s does not match %s", name, cs.ID)
+	}
+	expand := func(tpl map[string]string) map[string]string {
+		if tpl == nil {
+			return nil
+		}
+		ans := make(map[string]string, len(tpl))
+		for k, v := range tpl {
+			ans[k] = expandPlaceholders(v, parts)
+		}
+		return ans
+	}
+	ans := cs.clone()
+	ans.ID = name
+	ans.FullName = expand(cs.FullName)
+	ans.Description = expand(cs.Description)
+	return ans, nil
+}
+
+// Lookup finds a setup for a concrete corpus name. A setup with
+// an equal ID is preferred, then a variant with an equal ID (see
+// Registry.Variant). Otherwise, dynamic setups (see
+// CorpusSetup.IsDynamic) are tried and the most specific one
+// wins - i.e. the one with the most literal characters in its ID,
+// then the one with the fewest wildcards (and finally the first one
+// in alphabetical order to keep the result stable). A matching dynamic
+// setup is converted into a concrete one (see CorpusSetup.Concrete).
+func (r *Registry) Lookup(name string) (*CorpusSetup, bool) {
+	if ans, ok := r.setups[name]; ok && !ans.IsDynamic() {
+		return ans, true
+	}
+	if ans, ok := r.Variant(name); ok {
+		return ans, true
+	}
+	var best *CorpusSetup
+	var bestLiterals, bestWildcards int
+	for _, id := range r.IDs() {
+		setup := r.setups[id]
+		if !setup.IsDynamic() {
+			continue
+		}
+		if _, ok := setup.MatchID(name); !ok {
+			continue
+		}
+		literals, wildcards := idSpecificity(id)
+		if best == nil || literals > bestLiterals ||
+			literals == bestLiterals && wildcards < bestWildcards {
+			best, bestLiterals, bestWildcards = setup, literals, wildcards
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	ans, err := best.Concrete(name)
+	if err != nil {
+		// this should not happen as the name has been already matched
+		return nil, false
+	}
+	return ans, true
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchID(t *testing.T) {
+	setup := &CorpusSetup{ID: "intercorp_v*_*"}
+	parts, ok := setup.MatchID("intercorp_v16_en")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"intercorp_v16_en", "16", "en"}, parts)
+	_, ok = setup.MatchID("syn2020")
+	assert.False(t, ok)
+
+	parts, ok = (&CorpusSetup{ID: "syn.*"}).MatchID("syn.")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"syn.", ""}, parts)
+	_, ok = (&CorpusSetup{ID: "syn.*"}).MatchID("synx")
+	assert.False(t, ok)
+
+	_, ok = (&CorpusSetup{ID: "syn2020"}).MatchID("syn2020")
+	assert.True(t, ok)
+}
+
+func TestConcrete(t *testing.T) {
+	setup := &CorpusSetup{
+		ID:          "intercorp_v*_*",
+		FullName:    map[string]string{"en": "InterCorp v${1} (${2})", "cs": "InterCorp"},
+		Description: map[string]string{"en": "${0} costs $5", "cs": "$1x ${1}x ${3} $${2}"},
+		WebURL:      "https://example.com/${1}",
+	}
+	ans, err := setup.Concrete("intercorp_v16_en")
+	assert.NoError(t, err)
+	assert.Equal(t, "intercorp_v16_en", ans.ID)
+	assert.Equal(t, map[string]string{"en": "InterCorp v16 (en)", "cs": "InterCorp"}, ans.FullName)
+	assert.Equal(
+		t,
+		map[string]string{"en": "intercorp_v16_en costs $5", "cs": "$1x 16x ${3} $en"},
+		ans.Description,
+	)
+	assert.Equal(t, "https://example.com/${1}", ans.WebURL)
+	assert.Equal(t, "intercorp_v*_*", setup.ID)
+	assert.Equal(t, "InterCorp v${1} (${2})", setup.FullName["en"])
+
+	_, err = setup.Concrete("syn2020")
+	assert.Error(t, err)
+	_, err = (&CorpusSetup{ID: "syn2020"}).Concrete("syn2020")
+	assert.Error(t, err)
+}
+
+func TestConcreteDoesNotShareData(t *testing.T) {
+	setup := &CorpusSetup{
+		ID:             "intercorp_*",
+		PosAttrs:       PosAttrList{{Name: "word"}},
+		SrchKeywords:   []string{"parallel"},
+		TextProperties: TextTypeProperties{TextPropertyPubYear: {Name: "doc.pubyear"}},
+		Subcorpora:     map[string]Subcorpus{"fic": {ID: "fic"}},
+	}
+	ans, err := setup.Concrete("intercorp_v16")
+	assert.NoError(t, err)
+	ans.PosAttrs[0].Name = "lemma"
+	ans.SrchKeywords[0] = "x"
+	delete(ans.TextProperties, TextPropertyPubYear)
+	ans.Subcorpora["nfc"] = Subcorpus{ID: "nfc"}
+	assert.Equal(t, "word", setup.PosAttrs[0].Name)
+	assert.Equal(t, []string{"parallel"}, setup.SrchKeywords)
+	assert.Len(t, setup.TextProperties, 1)
+	assert.Len(t, setup.Subcorpora, 1)
+}
+
+func TestIDSpecificity(t *testing.T) {
+	literals, wildcards := idSpecificity("čeština_*")
+	assert.Equal(t, 8, literals)
+	assert.Equal(t, 1, wildcards)
+
+	reg := NewRegistry()
+	assert.NoError(t, reg.Add(&CorpusSetup{ID: "čč_*", MaximumRecords: 1}))
+	assert.NoError(t, reg.Add(&CorpusSetup{ID: "*_abcd", MaximumRecords: 2}))
+	ans, ok := reg.Lookup("čč_abcd")
+	assert.True(t, ok)
+	assert.Equal(t, 2, ans.MaximumRecords)
+}
+
+func TestRegistryLookup(t *testing.T) {
+	reg := NewRegistry()
+	for _, s := range []*CorpusSetup{
+		{ID: "intercorp_*", MaximumRecords: 1},
+		{ID: "intercorp_v16_*", MaximumRecords: 2},
+		{ID: "intercorp_v*_*", MaximumRecords: 3},
+		{ID: "intercorp_v16_cs", MaximumRecords: 4},
+		{ID: "*_v16_en", MaximumRecords: 5},
+	} {
+		assert.NoError(t, reg.Add(s))
+	}
+	cases := map[string]int{
+		"intercorp_v16_cs": 4,
+		"intercorp_v16_de": 2,
+		"intercorp_v15_de": 3,
+		"intercorp_x":      1,
+		"intercorp_v16_en": 2,
+		"other_v16_en":     5,
+	}
+	for name, expected := range cases {
+		ans, ok := reg.Lookup(name)
+		if assert.True(t, ok, name) {
+			assert.Equal(t, name, ans.ID)
+			assert.Equal(t, expected, ans.MaximumRecords, name)
+		}
+	}
+	_, ok := reg.Lookup("syn2020")
+	assert.False(t, ok)
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// jsonKey returns a JSON name of a struct field
+func jsonKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// extendValue merges struct values `base` and `child` field by field
+// into `ans` (see CorpusSetup.Extend for the semantics). Keys of the fields
+// (JSON names, nested ones separated by dots) are searched in `defined`
+// to find zero values which should be used anyway.
+func extendValue(base, child, ans reflect.Value, prefix string, defined map[string]bool) {
+	for i := 0; i < ans.NumField(); i++ {
+		field := ans.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := prefix + jsonKey(field)
+		b, c, dst := base.Field(i), child.Field(i), ans.Field(i)
+		switch field.Type.Kind() {
+		case reflect.Map:
+			if b.IsNil() && c.IsNil() {
+				continue
+			}
+			merged := reflect.MakeMapWithSize(field.Type, b.Len()+c.Len())
+			for _, src := range []reflect.Value{b, c} {
+				iter := src.MapRange()
+				for iter.Next() {
+					merged.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+			dst.Set(merged)
+		case reflect.Slice:
+			src := b
+			if !c.IsNil() {
+				src = c
+			}
+			if !src.IsNil() {
+				dst.Set(reflect.AppendSlice(reflect.MakeSlice(field.Type, 0, src.Len()), src))
+			}
+		case reflect.Struct:
+			extendValue(b, c, dst, key+".", defined)
+		default:
+			if defined[key] || !c.IsZero() {
+				dst.Set(c)
+
+			} else {
+				dst.Set(b)
+			}
+		}
+	}
+}
+
+func (cs *CorpusSetup) extend(base *CorpusSetup, defined map[string]bool) *CorpusSetup {
+	var ans CorpusSetup
+	extendValue(reflect.ValueOf(base).Elem(), reflect.ValueOf(cs).Elem(), reflect.ValueOf(&ans).Elem(), "", defined)
+	ans.ID = cs.ID
+	ans.Extends = cs.Extends
+	return &ans
+}
+
+// Extend creates a new setup by applying the setup to a `base` one.
+// Neither of the setups is modified. Merge semantics:
+//
+//   - maps (FullName, Description, TextProperties, Subcorpora, Variants)
+//     are merged by keys, an item of the extending setup replaces
+//     the whole base item with the same key,
+//   - slices (PosAttrs, Tagsets, ...) are replaced if defined; an empty
+//     slice (e.g. `[]` in JSON) is a valid value clearing the base one,
+//   - nested structs (SyntaxConcordance) are merged field by field,
+//   - other values are replaced if they are non-zero. Please note that
+//     this means e.g. HasPublicAudio cannot be switched off here.
+//     Registry.Resolve also replaces values explicitly defined
+//     in configuration files (see LoadDir) even if they are zero.
+//
+// The ID and Extends values are always taken from the extending setup.
+// The returned setup contains new maps and slices so it can be modified
+// without affecting the original setups.
+func (cs *CorpusSetup) Extend(base *CorpusSetup) *CorpusSetup {
+	return cs.extend(base, nil)
+}
+
+// clone returns a copy of the setup with new maps and slices
+// (the same way Extend does) so it can be modified without affecting
+// the original setup
+func (cs *CorpusSetup) clone() *CorpusSetup {
+	return cs.extend(cs, nil)
+}
+
+// ------
+
+// CyclicExtendsError is returned when setups extend each other
+// (directly or via other setups)
+type CyclicExtendsError struct {
+	Chain []string
+}
+
+func (e *CyclicExtendsError) Error() string {
+	return fmt.Sprintf("cyclic extends: %s", strings.Join(e.Chain, " -> "))
+}
+
+func (r *Registry) resolve(id string, chain []string) (*CorpusSetup, error) {
+	setup, ok := r.setups[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown corpus setup %s", id)
+	}
+	if slices.Contains(chain, id) {
+		return nil, &CyclicExtendsError{Chain: append(chain, id)}
+	}
+	if setup.Extends == "" {
+		return setup, nil
+	}
+	base, err := r.resolve(setup.Extends, append(chain, id))
+	if err != nil {
+		if _, ok := err.(*CyclicExtendsError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to resolve base of %s: %w", id, err)
+	}
+	return setup.extend(base, r.defined[id]), nil
+}
+
+// Resolve returns a fully resolved setup of a corpus with all the base
+// setups (see CorpusSetup.Extends) applied. Compared with CorpusSetup.Extend,
+// zero values explicitly defined in configuration files (see LoadDir)
+// replace the base values too. The setups stored in the registry are
+// not modified.
+func (r *Registry) Resolve(id string) (*CorpusSetup, error) {
+	return r.resolve(id, make([]string, 0, 5))
+}
+
+// ResolveAll replaces all the stored setups with their resolved versions
+// (see Resolve). In case of an error, the registry is not modified and
+// all the problems are returned joined.
+func (r *Registry) ResolveAll() error {
+	resolved := make(map[string]*CorpusSetup, len(r.setups))
+	errs := make([]error, 0, 3)
+	for _, id := range r.IDs() {
+		setup, err := r.Resolve(id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("corpus %s: %w", id, err))
+			continue
+		}
+		resolved[id] = setup
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	r.setups = resolved
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtend(t *testing.T) {
+	base := &CorpusSetup{
+		ID:             "syn2015",
+		FullName:       map[string]string{"en": "SYN2015", "cs": "SYN2015"},
+		PosAttrs:       PosAttrList{{Name: "word"}, {Name: "lemma"}},
+		SrchKeywords:   []string{"written"},
+		MaximumRecords: 100,
+		HasPublicAudio: true,
+		TextProperties: TextTypeProperties{TextPropertyPubYear: {Name: "doc.pubyear"}},
+		Subcorpora: map[string]Subcorpus{
+			"fic": {ID: "fic", Description: map[string]string{"en": "fiction"}},
+			"nfc": {ID: "nfc"},
+		},
+	}
+	child := &CorpusSetup{
+		ID:             "syn2020",
+		Extends:        "syn2015",
+		FullName:       map[string]string{"en": "SYN2020"},
+		SrchKeywords:   []string{},
+		MaximumRecords: 200,
+		Subcorpora:     map[string]Subcorpus{"fic": {ID: "fic"}},
+	}
+	ans := child.Extend(base)
+	assert.Equal(t, "syn2020", ans.ID)
+	assert.Equal(t, "syn2015", ans.Extends)
+	assert.Equal(t, map[string]string{"en": "SYN2020", "cs": "SYN2015"}, ans.FullName)
+	assert.Equal(t, base.PosAttrs, ans.PosAttrs)
+	assert.Equal(t, []string{}, ans.SrchKeywords)
+	assert.Equal(t, 200, ans.MaximumRecords)
+	assert.True(t, ans.HasPublicAudio)
+	assert.Equal(t, base.TextProperties, ans.TextProperties)
+	assert.Equal(t, map[string]Subcorpus{"fic": {ID: "fic"}, "nfc": {ID: "nfc"}}, ans.Subcorpora)
+	assert.Nil(t, ans.Variants)
+
+	// the original setups must stay untouched
+	ans.PosAttrs[0].Name = "x"
+	ans.FullName["de"] = "SYN2020"
+	assert.Equal(t, "word", base.PosAttrs[0].Name)
+	assert.Len(t, base.FullName, 2)
+	assert.Len(t, child.FullName, 1)
+}
+
+func TestExtendDefinedZeroValues(t *testing.T) {
+	base := &CorpusSetup{
+		ID:                "syn2015",
+		HasPublicAudio:    true,
+		MaximumRecords:    100,
+		WebURL:            "http://syn2015",
+		SyntaxConcordance: SyntaxConcordance{ParentAttr: "parent"},
+	}
+	child := &CorpusSetup{ID: "syn2020", Extends: "syn2015"}
+	ans := child.Extend(base)
+	assert.True(t, ans.HasPublicAudio)
+	assert.Equal(t, 100, ans.MaximumRecords)
+
+	ans = child.extend(
+		base,
+		map[string]bool{"hasPublicAudio": true, "webUrl": true, "syntaxConcordance.parentAttr": true},
+	)
+	assert.False(t, ans.HasPublicAudio)
+	assert.Equal(t, "", ans.WebURL)
+	assert.Equal(t, "", ans.SyntaxConcordance.ParentAttr)
+	assert.Equal(t, 100, ans.MaximumRecords)
+}
+
+// fillValue sets all the exported fields (recursively) to non-zero
+// values derived from `seed`
+func fillValue(v reflect.Value, seed string) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillValue(v.Field(i), seed+v.Type().Field(i).Name)
+			}
+		}
+	case reflect.String:
+		v.SetString(seed)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(int64(len(seed)))
+	case reflect.Slice:
+		item := reflect.New(v.Type().Elem()).Elem()
+		fillValue(item, seed)
+		v.Set(reflect.Append(reflect.MakeSlice(v.Type(), 0, 1), item))
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fillValue(key, seed)
+		item := reflect.New(v.Type().Elem()).Elem()
+		fillValue(item, seed)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, item)
+	case reflect.Pointer:
+		ptr := reflect.New(v.Type().Elem())
+		fillValue(ptr.Elem(), seed)
+		v.Set(ptr)
+	default:
+		panic(fmt.Sprintf("fillValue: unsupported kind %s", v.Kind()))
+	}
+}
+
+// TestExtendHandlesAllFields makes sure no field of CorpusSetup
+// gets lost in resolved setups
+func TestExtendHandlesAllFields(t *testing.T) {
+	var base, child CorpusSetup
+	fillValue(reflect.ValueOf(&base).Elem(), "base")
+	fillValue(reflect.ValueOf(&child).Elem(), "child")
+	child.ID = "child"
+	child.Extends = "base"
+
+	ans := (&CorpusSetup{ID: "child", Extends: "base"}).Extend(&base)
+	expected := base
+	expected.ID = "child"
+	expected.Extends = "base"
+	assert.Equal(t, &expected, ans)
+
+	ans = child.Extend(&base)
+	tp := reflect.TypeOf(child)
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		got := reflect.ValueOf(*ans).Field(i)
+		switch field.Type.Kind() {
+		case reflect.Map:
+			// both the keys are present, the child's value wins
+			assert.Equal(t, 2, got.Len(), field.Name)
+			for _, k := range reflect.ValueOf(child).Field(i).MapKeys() {
+				assert.Equal(
+					t,
+					reflect.ValueOf(child).Field(i).MapIndex(k).Interface(),
+					got.MapIndex(k).Interface(),
+					field.Name,
+				)
+			}
+		default:
+			assert.Equal(t, reflect.ValueOf(child).Field(i).Interface(), got.Interface(), field.Name)
+		}
+	}
+}
+
+func TestRegistryResolve(t *testing.T) {
+	reg := NewRegistry()
+	for _, s := range []*CorpusSetup{
+		{ID: "a", MaximumRecords: 10, WebURL: "http://a"},
+		{ID: "b", Extends: "a", MaximumRecords: 20},
+		{ID: "c", Extends: "b", ViewContextStruct: "s"},
+		{ID: "x", Extends: "y"},
+		{ID: "y", Extends: "z"},
+		{ID: "z", Extends: "x"},
+		{ID: "u", Extends: "unknown"},
+	} {
+		assert.NoError(t, reg.Add(s))
+	}
+	c, err := reg.Resolve("c")
+	assert.NoError(t, err)
+	assert.Equal(t, &CorpusSetup{ID: "c", Extends: "b", MaximumRecords: 20, WebURL: "http://a", ViewContextStruct: "s"}, c)
+	orig, _ := reg.Get("c")
+	assert.Equal(t, 0, orig.MaximumRecords)
+
+	_, err = reg.Resolve("x")
+	assert.EqualError(t, err, "cyclic extends: x -> y -> z -> x")
+	_, err = reg.Resolve("u")
+	assert.EqualError(t, err, "failed to resolve base of u: unknown corpus setup unknown")
+
+	err = reg.ResolveAll()
+	assert.Error(t, err)
+	orig, _ = reg.Get("c")
+	assert.Equal(t, 0, orig.MaximumRecords)
+}
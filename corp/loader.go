@@ -0,0 +1,646 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultsFileName is a name (without suffix) of a file with values
+// shared by all the corpora in a configuration directory
+const DefaultsFileName = "_defaults"
+
+// LoadError describes a problem found in a configuration file.
+// Line is a one-based line number; zero means the problem cannot
+// be attributed to a specific line (e.g. a missing value). Key is
+// a dot-separated path of the configuration key the problem relates
+// to (see FieldError); it is empty if unknown.
+type LoadError struct {
+	File string
+	Line int
+	Key  string
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+	}
+	if e.Key != "" {
+		return fmt.Sprintf("%s: %s: %s", e.File, e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// ------
+
+// LoadOptions configures LoadDir
+type LoadOptions struct {
+
+	// Env specifies an environment (e.g. "production") whose override
+	// files should be applied. With an empty value, no overrides are used.
+	Env string
+
+	// TagsetValidator is used to validate tagsets of the loaded setups.
+	// With a nil value, only the built-in tagsets are accepted
+	// (see CorpusSetup.ValidateWith).
+	TagsetValidator TagsetValidator
+}
+
+// configFile is a parsed configuration file
+type configFile struct {
+	path string
+	data []byte
+	tree map[string]any
+
+	// node is defined only for YAML files
+	node *yaml.Node
+}
+
+// pathLine finds a line where a key specified by its full dot-separated
+// path (e.g. `syntaxConcordance.parentAttr`) is defined. Arrays are not
+// supported in the path. Zero is returned if there is no such key.
+func (cf *configFile) pathLine(path string) int {
+	keys := strings.Split(path, ".")
+	if cf.node != nil {
+		return yamlPathLine(cf.node, keys)
+	}
+	if offset, ok := jsonPathOffset(cf.data, keys); ok {
+		return offsetLine(cf.data, offset)
+	}
+	return 0
+}
+
+// nearestPathLine works like pathLine but in case the key is not
+// found (e.g. because it is nested in an array), its closest
+// found parent is used.
+func (cf *configFile) nearestPathLine(path string) int {
+	for path != "" {
+		if ans := cf.pathLine(path); ans > 0 {
+			return ans
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return 0
+}
+
+// errorLine tries to find a line responsible for a JSON decoding error
+func (cf *configFile) errorLine(err error) int {
+	var synErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &synErr):
+		if cf.node == nil {
+			return offsetLine(cf.data, synErr.Offset)
+		}
+	case errors.As(err, &typeErr):
+		if cf.node == nil {
+			return offsetLine(cf.data, typeErr.Offset)
+		}
+		return cf.nearestPathLine(typeErr.Field)
+	}
+	return 0
+}
+
+func (cf *configFile) fail(line int, err error) *LoadError {
+	return &LoadError{File: cf.path, Line: line, Err: err}
+}
+
+func offsetLine(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+func yamlPathLine(node *yaml.Node, keys []string) int {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	var ans int
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return 0
+		}
+		var found bool
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				ans = node.Content[i].Line
+				node = node.Content[i+1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+	return ans
+}
+
+// jsonPathOffset streams JSON tokens and returns an offset
+// (just after the key) of the first object key matching the path
+func jsonPathOffset(data []byte, keys []string) (int64, bool) {
+	type frame struct {
+		isObject  bool
+		expectKey bool
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	stack := make([]frame, 0, 10)
+	path := make([]string, 0, 10)
+	valueDone := func() {
+		if n := len(stack); n > 0 && stack[n-1].isObject {
+			stack[n-1].expectKey = true
+			path = path[:len(path)-1]
+		}
+	}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, false
+		}
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{':
+				stack = append(stack, frame{isObject: true, expectKey: true})
+			case '[':
+				// keys nested in arrays never match the path
+				stack = append(stack, frame{})
+				path = append(path, "[]")
+			case ']':
+				stack = stack[:len(stack)-1]
+				path = path[:len(path)-1]
+				valueDone()
+			default:
+				stack = stack[:len(stack)-1]
+				valueDone()
+			}
+		case string:
+			if n := len(stack); n > 0 && stack[n-1].isObject && stack[n-1].expectKey {
+				stack[n-1].expectKey = false
+				path = append(path, v)
+				if slices.Equal(path, keys) {
+					return dec.InputOffset(), true
+				}
+
+			} else {
+				valueDone()
+			}
+		default:
+			valueDone()
+		}
+	}
+}
+
+// yamlNonStringKey returns the first mapping key which is not a string
+// (such keys cannot be converted to JSON)
+func yamlNonStringKey(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if tag := node.Content[i].ShortTag(); tag != "!!str" && tag != "!!merge" {
+				return node.Content[i]
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if ans := yamlNonStringKey(child); ans != nil {
+			return ans
+		}
+	}
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownKeys returns sorted dot-separated paths of keys of a decoded
+// configuration which do not match any field of the type `tp`. As in
+// encoding/json, keys are matched case-insensitively. Values of types
+// with custom JSON decoding are not searched and unknown keys nested in
+// arrays are reported with the path of the array.
+func unknownKeys(value any, tp reflect.Type, path string) []string {
+	if tp.Kind() == reflect.Pointer {
+		tp = tp.Elem()
+	}
+	if reflect.PointerTo(tp).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	join := func(k string) string {
+		if path == "" {
+			return k
+		}
+		return path + "." + k
+	}
+	ans := make([]string, 0, 5)
+	switch tp.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		fields := make(map[string]reflect.Type, tp.NumField())
+		for i := 0; i < tp.NumField(); i++ {
+			if f := tp.Field(i); f.IsExported() {
+				fields[strings.ToLower(jsonKey(f))] = f.Type
+			}
+		}
+		for _, k := range sortedKeys(obj) {
+			ftp, ok := fields[strings.ToLower(k)]
+			if !ok {
+				ans = append(ans, join(k))
+				continue
+			}
+			ans = append(ans, unknownKeys(obj[k], ftp, join(k))...)
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, k := range sortedKeys(obj) {
+			ans = append(ans, unknownKeys(obj[k], tp.Elem(), join(k))...)
+		}
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		for _, item := range items {
+			ans = append(ans, unknownKeys(item, tp.Elem(), path)...)
+		}
+	}
+	return ans
+}
+
+// decodeSetup strictly decodes a JSON representation
+// of a (possibly partial) corpus configuration
+func decodeSetup(data []byte) (*CorpusSetup, error) {
+	var ans CorpusSetup
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ans); err != nil {
+		return nil, err
+	}
+	return &ans, nil
+}
+
+// parseConfigFile reads a JSON or YAML file with a (possibly partial)
+// corpus configuration. Besides syntax, the file is also tested for
+// unknown fields and invalid value types.
+func parseConfigFile(path string) (*configFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &LoadError{File: path, Err: err}
+	}
+	ans := &configFile{path: path, data: data}
+	jsonData := data
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&ans.tree); err != nil {
+			return nil, ans.fail(ans.errorLine(err), err)
+		}
+
+	} else {
+		ans.node = new(yaml.Node)
+		if err := yaml.Unmarshal(data, ans.node); err != nil {
+			// yaml.v3 does not provide a typed syntax error,
+			// the line is part of the message
+			return nil, ans.fail(0, err)
+		}
+		if key := yamlNonStringKey(ans.node); key != nil {
+			return nil, ans.fail(key.Line, fmt.Errorf("non-string key `%s`", key.Value))
+		}
+		if err := ans.node.Decode(&ans.tree); err != nil {
+			return nil, ans.fail(0, err)
+		}
+		jsonData, err = json.Marshal(ans.tree)
+		if err != nil {
+			return nil, ans.fail(0, err)
+		}
+	}
+	if ans.tree == nil {
+		ans.tree = make(map[string]any)
+	}
+	if keys := unknownKeys(ans.tree, reflect.TypeOf(CorpusSetup{}), ""); len(keys) > 0 {
+		return nil, ans.fail(ans.nearestPathLine(keys[0]), fmt.Errorf("unknown field %s", keys[0]))
+	}
+	if _, err := decodeSetup(jsonData); err != nil {
+		return nil, ans.fail(ans.errorLine(err), err)
+	}
+	return ans, nil
+}
+
+// mergeTrees merges decoded configurations. Objects are merged
+// recursively, all the other values (including arrays) from `override`
+// replace the ones from `base`. The arguments are not modified.
+func mergeTrees(base, override map[string]any) map[string]any {
+	ans := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		ans[k] = v
+	}
+	for k, v := range override {
+		vMap, ok1 := v.(map[string]any)
+		baseMap, ok2 := ans[k].(map[string]any)
+		if ok1 && ok2 {
+			ans[k] = mergeTrees(baseMap, vMap)
+
+		} else {
+			ans[k] = v
+		}
+	}
+	return ans
+}
+
+// ------
+
+type dirEntries struct {
+	defaults     []string
+	corpora      []string
+	envOverrides map[string]string
+}
+
+func isConfigFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".json" || ext == ".yaml" || ext == ".yml"
+}
+
+// scanConfigDir sorts files of a configuration directory. Main files
+// are named `NAME.json` (or `NAME.yaml`, `NAME.yml`), override files
+// `NAME.ENV.json`. Overrides for other environments are ignored.
+func scanConfigDir(dir, env string) (*dirEntries, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load corpora from %s: %w", dir, err)
+	}
+	ans := &dirEntries{envOverrides: make(map[string]string)}
+	mainFiles := make(map[string]string)
+	envFiles := make([]string, 0, 10)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !isConfigFile(name) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		base, fileEnv, isOverride := strings.Cut(strings.TrimSuffix(name, filepath.Ext(name)), ".")
+		if base == "" || strings.Contains(fileEnv, ".") || isOverride && fileEnv == "" {
+			return nil, &LoadError{File: path, Err: fmt.Errorf("invalid configuration file name")}
+		}
+		if isOverride {
+			if fileEnv == env {
+				envFiles = append(envFiles, path)
+			}
+			continue
+		}
+		if prev, ok := mainFiles[base]; ok {
+			return nil, &LoadError{File: path, Err: fmt.Errorf("conflicts with %s", prev)}
+		}
+		mainFiles[base] = path
+		if base == DefaultsFileName {
+			ans.defaults = append(ans.defaults, path)
+
+		} else {
+			ans.corpora = append(ans.corpora, path)
+		}
+	}
+	for _, path := range envFiles {
+		name := filepath.Base(path)
+		base, _, _ := strings.Cut(name, ".")
+		mainPath, ok := mainFiles[base]
+		if !ok {
+			return nil, &LoadError{File: path, Err: fmt.Errorf("no configuration to override")}
+		}
+		if prev, ok := ans.envOverrides[mainPath]; ok {
+			return nil, &LoadError{File: path, Err: fmt.Errorf("conflicts with %s", prev)}
+		}
+		ans.envOverrides[mainPath] = path
+		if base == DefaultsFileName {
+			ans.defaults = append(ans.defaults, path)
+		}
+	}
+	sort.Strings(ans.corpora)
+	return ans, nil
+}
+
+// LoadDir loads corpus configurations from a directory (non-recursively).
+// Each corpus is defined in its own JSON or YAML file (detected by the
+// ".json", ".yaml" and ".yml" suffixes). Values shared by all the corpora
+// can be defined in a `_defaults` file (see DefaultsFileName).
+// Environment-specific values can be defined in override files named
+// `NAME.ENV.json` (e.g. `syn2020.production.json` or
+// `_defaults.production.yaml`) which are applied only if `opts.Env`
+// matches.
+//
+// Configurations are merged in the order defaults, defaults override,
+// corpus file, corpus override. Objects are merged recursively, all
+// the other values (including arrays) are replaced. Setups extending
+// other setups (see CorpusSetup.Extends) do not use the defaults directly
+// as they inherit them from their base setups. All the setups in the
+// returned registry are resolved (see Registry.Resolve). Values explicitly
+// defined in the files replace inherited ones even if they are zero
+// values (e.g. `hasPublicAudio: false`).
+//
+// Unknown fields are rejected and the resulting configurations are
+// validated (see CorpusSetup.ValidateWith and LoadOptions.TagsetValidator).
+// Problems found in different files are reported all at once (see
+// errors.Join) as *LoadError values with file names and line numbers
+// where possible. Validation problems are reported for the file
+// with the highest priority defining the respective key (see FieldError),
+// i.e. the corpus override, the corpus file, files of base setups and
+// defaults. Problems related to missing keys are reported for the corpus
+// file along with the key.
+func LoadDir(dir string, opts LoadOptions) (*Registry, error) {
+	entries, err := scanConfigDir(dir, opts.Env)
+	if err != nil {
+		return nil, err
+	}
+	defaults := make(map[string]any)
+	defaultFiles := make([]*configFile, 0, len(entries.defaults))
+	for _, path := range entries.defaults {
+		cf, err := parseConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range []string{"id", "extends"} {
+			if _, ok := cf.tree[key]; ok {
+				return nil, cf.fail(cf.pathLine(key), fmt.Errorf("defaults cannot define %s", key))
+			}
+		}
+		defaults = mergeTrees(defaults, cf.tree)
+		defaultFiles = append(defaultFiles, cf)
+	}
+
+	ans := NewRegistry()
+	files := make(map[string][]*configFile)
+	errs := make([]error, 0, len(entries.corpora))
+	for _, path := range entries.corpora {
+		loaded, err := loadCorpusFile(path, entries.envOverrides[path], defaults)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := ans.Add(loaded.setup); err != nil {
+			errs = append(errs, &LoadError{File: path, Err: err})
+			continue
+		}
+		ans.defined[loaded.setup.ID] = loaded.defined
+		files[loaded.setup.ID] = loaded.files
+	}
+	// locate finds a file (and a line) defining a configuration key
+	// of a setup. Files are searched in the order of their priority,
+	// i.e. the setup's own files, files of its base setups, defaults.
+	locate := func(id, key string) *LoadError {
+		candidates := make([]*configFile, 0, 10)
+		visited := make(map[string]bool)
+		for cur := id; cur != "" && !visited[cur]; {
+			setup, ok := ans.setups[cur]
+			if !ok {
+				break
+			}
+			visited[cur] = true
+			for i := len(files[cur]) - 1; i >= 0; i-- {
+				candidates = append(candidates, files[cur][i])
+			}
+			cur = setup.Extends
+		}
+		for i := len(defaultFiles) - 1; i >= 0; i-- {
+			candidates = append(candidates, defaultFiles[i])
+		}
+		for _, cf := range candidates {
+			if line := cf.pathLine(key); line > 0 {
+				return &LoadError{File: cf.path, Line: line, Key: key}
+			}
+		}
+		return &LoadError{File: files[id][0].path, Key: key}
+	}
+	// setups are resolved and validated even if some of the files
+	// failed to load so all the problems are reported at once
+	resolved := make(map[string]*CorpusSetup, len(ans.setups))
+	for _, id := range ans.IDs() {
+		setup, err := ans.Resolve(id)
+		if err != nil {
+			errs = append(errs, &LoadError{File: files[id][0].path, Err: err})
+			continue
+		}
+		if err := setup.ValidateWith(opts.TagsetValidator); err != nil {
+			for _, verr := range unwrapJoined(err) {
+				var ferr *FieldError
+				if errors.As(verr, &ferr) {
+					lerr := locate(id, ferr.Key)
+					lerr.Err = verr
+					errs = append(errs, lerr)
+
+				} else {
+					errs = append(errs, &LoadError{File: files[id][0].path, Err: verr})
+				}
+			}
+			continue
+		}
+		resolved[id] = setup
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	ans.setups = resolved
+	return ans, nil
+}
+
+// unwrapJoined returns errors joined by errors.Join
+// or just the error itself for other errors
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// loadedSetup is a corpus configuration along with its sources
+type loadedSetup struct {
+	setup *CorpusSetup
+
+	// files contains the main file and the override (if defined)
+	files []*configFile
+
+	// defined contains dot-separated paths of all the keys
+	// defined in the merged configuration tree
+	defined map[string]bool
+}
+
+// loadCorpusFile loads a corpus configuration file along with its
+// environment override. Defaults are applied only to setups which
+// do not extend other setups (otherwise they would replace values
+// inherited from the base setup). The result is not validated.
+func loadCorpusFile(path, overridePath string, defaults map[string]any) (*loadedSetup, error) {
+	cf, err := parseConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []*configFile{cf}
+	tree := cf.tree
+	if overridePath != "" {
+		ovr, err := parseConfigFile(overridePath)
+		if err != nil {
+			return nil, err
+		}
+		tree = mergeTrees(tree, ovr.tree)
+		files = append(files, ovr)
+	}
+	if _, ok := tree["extends"]; !ok {
+		tree = mergeTrees(defaults, tree)
+	}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, cf.fail(0, err)
+	}
+	setup, err := decodeSetup(data)
+	if err != nil {
+		return nil, cf.fail(0, err)
+	}
+	ans := &loadedSetup{setup: setup, files: files, defined: make(map[string]bool)}
+	addTreeKeys(tree, "", ans.defined)
+	return ans, nil
+}
+
+// addTreeKeys adds dot-separated paths of all the object keys
+// of a decoded configuration to `ans` (arrays are not searched)
+func addTreeKeys(tree map[string]any, prefix string, ans map[string]bool) {
+	for k, v := range tree {
+		ans[prefix+k] = true
+		if sub, ok := v.(map[string]any); ok {
+			addTreeKeys(sub, prefix+k+".", ans)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const testDefaults = `{
+	"posAttrs": [{"name": "word"}, {"name": "lemma"}],
+	"MaximumTokenContextWindow": 50,
+	"fullName": {"en": "unnamed"},
+	"maximumRecords": 100
+}`
+
+func TestLoadDir(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"_defaults.json":            testDefaults,
+		"_defaults.production.yaml": "maximumRecords: 1000\n",
+		"syn2020.json": `{
+			"id": "syn2020",
+			"fullName": {"cs": "SYN2020"},
+			"description": {"en": "reference corpus"}
+		}`,
+		"syn2020.production.json": `{"webUrl": "https://example.com/syn2020"}`,
+		"syn2020.devel.json":      `{"webUrl": "http://localhost"}`,
+		"ortofon.yml": "id: ortofon\n" +
+			"description:\n  en: spoken corpus\n" +
+			"posAttrs:\n  - name: word\n",
+		"README.md": "not a config",
+	})
+	reg, err := LoadDir(dir, LoadOptions{Env: "production"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ortofon", "syn2020"}, reg.IDs())
+
+	syn, ok := reg.Get("syn2020")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"en": "unnamed", "cs": "SYN2020"}, syn.FullName)
+	assert.Equal(t, 1000, syn.MaximumRecords)
+	assert.Equal(t, "https://example.com/syn2020", syn.WebURL)
+	assert.Equal(t, ContextWindow(50), syn.MaximumTokenContextWindow)
+
+	orto, _ := reg.Get("ortofon")
+	assert.Equal(t, PosAttrList{{Name: "word"}}, orto.PosAttrs)
+
+	reg, err = LoadDir(dir, LoadOptions{})
+	assert.NoError(t, err)
+	syn, _ = reg.Get("syn2020")
+	assert.Equal(t, 100, syn.MaximumRecords)
+	assert.Equal(t, "", syn.WebURL)
+}
+
+func TestLoadDirErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"_defaults.json": testDefaults,
+		"a.json":         "{\n\t\"id\": \"a\",\n\t\"description\": {\"en\": \"A\"},\n\t\"fooBar\": 1\n}",
+		"b.yaml":         "id: b\ndescription:\n  en: B\nmaximumRecords: many\n",
+		"c.json":         "{\n\t\"id\": \"c\",\n\t\"maximumRecords\": \"x\"\n}",
+		"d.json":         `{"id": "d"}`,
+		"e.json":         `{"id": "e", "description": {"en": "E"}}`,
+		"e2.json":        `{"id": "e", "description": {"en": "E2"}}`,
+		"f.yaml":         "id: f\n  description: x\n",
+		"g.yaml":         "id: g\n1: x\n",
+		"h.yaml":         "id: h\nsyntaxConcordance:\n  parentAttr: parent\n  foo: 1\n",
+		"i.json":         "{\n\t\"id\": \"i\",\n\t\"posAttrs\": [\n\t\t{\"name\": \"word\", \"foo\": 1}\n\t]\n}",
+	})
+	_, err := LoadDir(dir, LoadOptions{})
+	assert.Error(t, err)
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !assert.True(t, ok) {
+		return
+	}
+	expected := []struct {
+		file string
+		line int
+	}{
+		{"a.json", 4},
+		{"b.yaml", 4},
+		{"c.json", 3},
+		{"e2.json", 0},
+		{"f.yaml", 0},
+		{"g.yaml", 2},
+		{"h.yaml", 4},
+		{"i.json", 3},
+		{"d.json", 0},
+	}
+	errs := joined.Unwrap()
+	if assert.Len(t, errs, len(expected)) {
+		for i, exp := range expected {
+			var lerr *LoadError
+			if assert.True(t, errors.As(errs[i], &lerr), errs[i].Error()) {
+				assert.Equal(t, exp.file, filepath.Base(lerr.File))
+				assert.Equal(t, exp.line, lerr.Line, lerr.Error())
+			}
+		}
+	}
+	assert.ErrorContains(t, errs[3], "duplicate corpus setup e")
+	assert.ErrorContains(t, errs[4], "line 2")
+	assert.ErrorContains(t, errs[5], "non-string key `1`")
+	assert.ErrorContains(t, errs[6], "unknown field syntaxConcordance.foo")
+	assert.ErrorContains(t, errs[7], "unknown field posAttrs.foo")
+	assert.ErrorContains(t, errs[8], "missing `en` description")
+}
+
+func TestLoadDirValidationErrorsLocation(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"_defaults.yaml": "MaximumTokenContextWindow: 50\n" +
+			"concTextPropsAttrs:\n  - title\n",
+		"base.json": "{\n\t\"id\": \"base\",\n\t\"description\": {\"en\": \"base\"},\n" +
+			"\t\"syntaxConcordance\": {\n\t\t\"parentAttr\": \"parent\"\n\t}\n}",
+		"child.yaml":      "id: child\nextends: base\ndescription:\n  en: child\ntagsets:\n  - foo\n",
+		"child.prod.yaml": "bibIdAttr: doc.id\n",
+		"other.json":      `{"id": "other"}`,
+	})
+	_, err := LoadDir(dir, LoadOptions{Env: "prod"})
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !assert.True(t, ok) {
+		return
+	}
+	expected := []struct {
+		file string
+		line int
+		key  string
+	}{
+		{"_defaults.yaml", 2, "concTextPropsAttrs"},
+		{"base.json", 5, "syntaxConcordance.parentAttr"},
+		{"child.yaml", 5, "tagsets"},
+		{"_defaults.yaml", 2, "concTextPropsAttrs"},
+		{"child.prod.yaml", 1, "bibIdAttr"},
+		{"base.json", 5, "syntaxConcordance.parentAttr"},
+		{"other.json", 0, "description"},
+		{"_defaults.yaml", 2, "concTextPropsAttrs"},
+	}
+	errs := joined.Unwrap()
+	if assert.Len(t, errs, len(expected)) {
+		for i, exp := range expected {
+			var lerr *LoadError
+			if assert.True(t, errors.As(errs[i], &lerr), errs[i].Error()) {
+				assert.Equal(t, exp.file, filepath.Base(lerr.File), lerr.Error())
+				assert.Equal(t, exp.line, lerr.Line, lerr.Error())
+				assert.Equal(t, exp.key, lerr.Key)
+			}
+		}
+	}
+	assert.ErrorContains(t, errs[6], "other.json: description: corpus other: missing `en` description")
+}
+
+func TestJSONPathOffset(t *testing.T) {
+	data := []byte("{\n" +
+		"\t\"a\": [{\"b\": 1}, [2, {\"c\": 3}]],\n" +
+		"\t\"b\": {\n" +
+		"\t\t\"a\": \"c\",\n" +
+		"\t\t\"c\": {\"d\": null}\n" +
+		"\t},\n" +
+		"\t\"e\": 1\n" +
+		"}")
+	for path, line := range map[string]int{"a": 2, "b": 3, "b.a": 4, "b.c": 5, "b.c.d": 5, "e": 7} {
+		offset, ok := jsonPathOffset(data, strings.Split(path, "."))
+		if assert.True(t, ok, path) {
+			assert.Equal(t, line, offsetLine(data, offset), path)
+		}
+	}
+	for _, path := range []string{"c", "a.b", "b.a.c", "x"} {
+		_, ok := jsonPathOffset(data, strings.Split(path, "."))
+		assert.False(t, ok, path)
+	}
+}
+
+func TestLoadDirInvalidFiles(t *testing.T) {
+	cases := []map[string]string{
+		{"_defaults.json": `{"id": "x"}`},
+		{"a.json": `{}`, "a.yaml": ``},
+		{"a.prod.json": `{}`},
+		{"a.b.c.json": `{}`},
+	}
+	for _, files := range cases {
+		_, err := LoadDir(writeConfigFiles(t, files), LoadOptions{Env: "prod"})
+		var lerr *LoadError
+		assert.True(t, errors.As(err, &lerr), "%v", files)
+	}
+}
+
+func TestLoadDirExtends(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"_defaults.json": testDefaults,
+		"syn2015.json":   `{"id": "syn2015", "description": {"en": "SYN2015"}, "maximumRecords": 500}`,
+		"syn2020.yaml":   "id: syn2020\nextends: syn2015\nfullName:\n  cs: SYN2020\n",
+		"cyc1.json":      `{"id": "cyc1", "extends": "cyc2"}`,
+		"cyc2.json":      `{"id": "cyc2", "extends": "cyc1"}`,
+	})
+	_, err := LoadDir(dir, LoadOptions{})
+	var lerr *LoadError
+	if assert.ErrorAs(t, err, &lerr) {
+		assert.Equal(t, "cyc1.json", filepath.Base(lerr.File))
+		assert.ErrorContains(t, lerr, "cyclic extends: cyc1 -> cyc2 -> cyc1")
+	}
+
+	assert.NoError(t, os.Remove(filepath.Join(dir, "cyc1.json")))
+	assert.NoError(t, os.Remove(filepath.Join(dir, "cyc2.json")))
+	reg, err := LoadDir(dir, LoadOptions{})
+	assert.NoError(t, err)
+	syn, _ := reg.Get("syn2020")
+	assert.Equal(t, 500, syn.MaximumRecords)
+	assert.Equal(t, map[string]string{"en": "unnamed", "cs": "SYN2020"}, syn.FullName)
+	assert.Equal(t, map[string]string{"en": "SYN2015"}, syn.Description)
+
+	assert.NoError(t, os.WriteFile(
+		filepath.Join(dir, "syn2015.json"),
+		[]byte(`{"id": "syn2015", "description": {"en": "SYN2015"}, "hasPublicAudio": true, "webUrl": "http://x"}`),
+		0o644,
+	))
+	assert.NoError(t, os.WriteFile(
+		filepath.Join(dir, "syn2020.yaml"),
+		[]byte("id: syn2020\nextends: syn2015\nhasPublicAudio: false\n"),
+		0o644,
+	))
+	reg, err = LoadDir(dir, LoadOptions{})
+	assert.NoError(t, err)
+	syn, _ = reg.Get("syn2020")
+	assert.False(t, syn.HasPublicAudio)
+	assert.Equal(t, "http://x", syn.WebURL)
+
+	_, err = LoadDir(writeConfigFiles(t, map[string]string{"_defaults.json": `{"extends": "x"}`}), LoadOptions{})
+	assert.ErrorContains(t, err, "defaults cannot define extends")
+}
@@ -17,6 +17,9 @@
 package corp
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/czcorpus/cnc-gokit/collections"
@@ -156,6 +159,10 @@ type CorpusSetup struct {
 	// Size represents size of corpus in tokens. In MQuery, this does not
 	// have to be configured as MQuery can get the value via Manatee.
 	Size int64 `json:"size,omitempty"`
+
+	// Extends specifies an ID of a base setup the corpus inherits
+	// all the non-configured values from (see CorpusSetup.Extend).
+	Extends string `json:"extends,omitempty"`
 }
 
 func (cs *CorpusSetup) LocaleDescription(lang string) string {
@@ -166,6 +173,8 @@ func (cs *CorpusSetup) LocaleDescription(lang string) string {
 	return cs.Description["en"]
 }
 
+// IsDynamic tests whether the setup ID is a pattern matching
+// multiple corpora (e.g. `intercorp_v16_*`). See also Registry.Lookup.
 func (cs *CorpusSetup) IsDynamic() bool {
 	return strings.Contains(cs.ID, "*")
 }
@@ -188,3 +197,170 @@ func (cs *CorpusSetup) KnownStructures() []string {
 	ans = append(ans, tmp...)
 	return ans
 }
+
+func isStructAttr(s string) bool {
+	st, attr, ok := strings.Cut(s, ".")
+	return ok && st != "" && attr != "" && !strings.Contains(attr, ".")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	ans := make([]string, 0, len(m))
+	for k := range m {
+		ans = append(ans, k)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
+// FieldError is a validation problem related to a configuration
+// key. Key is a dot-separated path of JSON keys (e.g. `posAttrs` or
+// `syntaxConcordance.parentAttr`). It allows for locating the problem
+// in configuration files (see LoadDir).
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// TagsetValidator tests whether a tagset is known. It allows for
+// validating tagsets registered at runtime (see tagset.Registry.Validate).
+type TagsetValidator func(ts SupportedTagset) error
+
+// Validate tests the configuration for consistency. Unlike most
+// validation functions, it does not stop at the first problem and
+// returns all the found problems joined (see errors.Join).
+// Please note that optional items (e.g. descriptions of positional
+// attributes) are validated only if they are defined.
+// Tagsets are validated against the built-in ones only
+// (see SupportedTagset.Validate and ValidateWith).
+func (cs *CorpusSetup) Validate() error {
+	return cs.ValidateWith(nil)
+}
+
+// ValidateWith works like Validate but tagsets are tested using
+// the provided validator. A nil validator falls back
+// to SupportedTagset.Validate.
+func (cs *CorpusSetup) ValidateWith(tagsets TagsetValidator) error {
+	if tagsets == nil {
+		tagsets = SupportedTagset.Validate
+	}
+	errs := make([]error, 0, 10)
+	addErr := func(key, msg string, args ...any) {
+		errs = append(
+			errs,
+			&FieldError{
+				Key: key,
+				Err: fmt.Errorf("corpus %s: "+msg, append([]any{cs.ID}, args...)...),
+			},
+		)
+	}
+	if cs.ID == "" {
+		errs = append(errs, &FieldError{Key: "id", Err: fmt.Errorf("missing corpus ID")})
+	}
+	if cs.Description["en"] == "" {
+		addErr("description", "missing `en` description")
+	}
+	for _, pa := range cs.PosAttrs {
+		if pa.Name == "" {
+			addErr("posAttrs", "positional attribute with empty name")
+
+		} else if len(pa.Description) > 0 && pa.Description["en"] == "" {
+			addErr("posAttrs", "missing `en` description of positional attribute %s", pa.Name)
+		}
+	}
+	for _, ts := range cs.Tagsets {
+		if err := tagsets(ts); err != nil {
+			addErr("tagsets", "%w", err)
+		}
+	}
+	props := make([]string, 0, len(cs.TextProperties))
+	for k := range cs.TextProperties {
+		props = append(props, string(k))
+	}
+	sort.Strings(props)
+	for _, k := range props {
+		prop := TextProperty(k)
+		key := "textProperties." + k
+		if !prop.Validate() {
+			addErr(key, "invalid text property %s", prop)
+
+		} else if !isStructAttr(cs.TextProperties[prop].Name) {
+			addErr(key, "text property %s must be mapped to a structural attribute (struct.attr)", prop)
+		}
+	}
+	for _, attr := range cs.ConcTextPropsAttrs {
+		if !isStructAttr(attr) {
+			addErr("concTextPropsAttrs", "invalid concTextPropsAttrs item `%s` (expected struct.attr)", attr)
+		}
+	}
+	if cs.BibLabelAttr != "" && cs.BibIDAttr == "" {
+		addErr("bibLabelAttr", "bibLabelAttr requires bibIdAttr")
+
+	} else if cs.BibLabelAttr == "" && cs.BibIDAttr != "" {
+		addErr("bibIdAttr", "bibIdAttr requires bibLabelAttr")
+	}
+	for _, item := range [][2]string{{"bibLabelAttr", cs.BibLabelAttr}, {"bibIdAttr", cs.BibIDAttr}} {
+		if item[1] != "" && !isStructAttr(item[1]) {
+			addErr(item[0], "invalid bibliography attribute `%s` (expected struct.attr)", item[1])
+		}
+	}
+	if cs.SyntaxConcordance.ParentAttr != "" && !cs.PosAttrs.Contains(cs.SyntaxConcordance.ParentAttr) {
+		addErr(
+			"syntaxConcordance.parentAttr",
+			"syntaxConcordance.parentAttr %s is not a positional attribute",
+			cs.SyntaxConcordance.ParentAttr,
+		)
+	}
+	for _, attr := range cs.SyntaxConcordance.ResultAttrs {
+		if !cs.PosAttrs.Contains(attr) {
+			addErr(
+				"syntaxConcordance.resultAttrs",
+				"syntaxConcordance.resultAttrs item %s is not a positional attribute",
+				attr,
+			)
+		}
+	}
+	if cs.MaximumTokenContextWindow <= 0 {
+		addErr("MaximumTokenContextWindow", "MaximumTokenContextWindow must be a positive number")
+	}
+	for _, k := range sortedKeys(cs.Subcorpora) {
+		subc := cs.Subcorpora[k]
+		key := "subcorpora." + k
+		if len(subc.Description) > 0 && subc.Description["en"] == "" {
+			addErr(key, "missing `en` description of subcorpus %s", k)
+		}
+		for _, attr := range subc.Selections.Attrs() {
+			if _, ok := subc.TextTypes[attr]; ok {
+				addErr(key, "subcorpus %s: attribute %s is defined in both textTypes and selections", k, attr)
+			}
+		}
+		if err := subc.Selections.Validate(cs.TextProperties); err != nil {
+			addErr(key, "subcorpus %s: %w", k, err)
+		}
+	}
+	variantIDs := make(map[string]string, len(cs.Variants))
+	for _, k := range sortedKeys(cs.Variants) {
+		v := cs.Variants[k]
+		key := "variants." + k
+		if v.ID == "" {
+			addErr(key, "variant %s has no ID", k)
+
+		} else if prev, ok := variantIDs[v.ID]; ok {
+			addErr(key, "variants %s and %s have the same ID %s", prev, k, v.ID)
+
+		} else {
+			variantIDs[v.ID] = k
+		}
+		if len(v.Description) > 0 && v.Description["en"] == "" {
+			addErr(key, "missing `en` description of variant %s", k)
+		}
+	}
+	return errors.Join(errs...)
+}
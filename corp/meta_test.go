@@ -0,0 +1,112 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validSetup() *CorpusSetup {
+	return &CorpusSetup{
+		ID:          "syn2020",
+		Description: map[string]string{"en": "a reference corpus", "cs": "referenční korpus"},
+		PosAttrs: PosAttrList{
+			{Name: "word"},
+			{Name: "lemma", Description: map[string]string{"en": "lemma"}},
+			{Name: "parent"},
+		},
+		SyntaxConcordance:         SyntaxConcordance{ParentAttr: "parent", ResultAttrs: []string{"word", "lemma"}},
+		ConcTextPropsAttrs:        []string{"doc.title"},
+		TextProperties:            TextTypeProperties{TextPropertyPubYear: {Name: "doc.pubyear"}},
+		MaximumTokenContextWindow: 50,
+		BibLabelAttr:              "doc.title",
+		BibIDAttr:                 "doc.id",
+		Tagsets:                   []SupportedTagset{TagsetCSCNC2020},
+		Subcorpora: map[string]Subcorpus{
+			"nineties": {Selections: TTSelections{"doc.pubyear": {Range: &NumRange{To: intPtr(1999)}}}},
+		},
+	}
+}
+
+func TestCorpusSetupValidateOK(t *testing.T) {
+	assert.NoError(t, validSetup().Validate())
+}
+
+func TestCorpusSetupValidateAggregates(t *testing.T) {
+	setup := validSetup()
+	setup.Description = map[string]string{"cs": "referenční korpus"}
+	setup.Tagsets = append(setup.Tagsets, "foo")
+	setup.TextProperties["colour"] = TTPropertyConf{Name: "doc.colour"}
+	setup.ConcTextPropsAttrs = append(setup.ConcTextPropsAttrs, "title")
+	setup.BibIDAttr = ""
+	setup.SyntaxConcordance.ResultAttrs = append(setup.SyntaxConcordance.ResultAttrs, "deprel")
+	setup.MaximumTokenContextWindow = 0
+	setup.Subcorpora["nineties"] = Subcorpus{Selections: TTSelections{"doc.txtype": {Range: &NumRange{To: intPtr(1)}}}}
+	err := setup.Validate()
+	assert.Error(t, err)
+	msgs := strings.Split(err.Error(), "\n")
+	assert.Len(t, msgs, 8)
+	for i, expected := range []string{
+		"missing `en` description",
+		"invalid tagset type: foo",
+		"invalid text property colour",
+		"invalid concTextPropsAttrs item `title`",
+		"bibLabelAttr requires bibIdAttr",
+		"resultAttrs item deprel",
+		"MaximumTokenContextWindow",
+		"subcorpus nineties: invalid selection of doc.txtype",
+	} {
+		assert.Contains(t, msgs[i], expected)
+		assert.True(t, strings.HasPrefix(msgs[i], "corpus syn2020: "))
+	}
+}
+
+func TestCorpusSetupValidateBibAttrs(t *testing.T) {
+	setup := validSetup()
+	setup.BibLabelAttr = ""
+	assert.ErrorContains(t, setup.Validate(), "bibIdAttr requires bibLabelAttr")
+	setup.BibIDAttr = ""
+	assert.NoError(t, setup.Validate())
+}
+
+func TestCorpusSetupValidateWith(t *testing.T) {
+	setup := validSetup()
+	setup.Tagsets = []SupportedTagset{"sk_snk"}
+	assert.ErrorContains(t, setup.Validate(), "invalid tagset type: sk_snk")
+	known := func(ts SupportedTagset) error {
+		if ts == "sk_snk" {
+			return nil
+		}
+		return fmt.Errorf("unknown tagset %s", ts)
+	}
+	assert.NoError(t, setup.ValidateWith(known))
+	setup.Tagsets = append(setup.Tagsets, TagsetUD)
+	assert.ErrorContains(t, setup.ValidateWith(known), "unknown tagset ud")
+}
+
+func TestCorpusSetupValidateSelectionsConflict(t *testing.T) {
+	setup := validSetup()
+	setup.Subcorpora["both"] = Subcorpus{
+		TextTypes:  TextTypes{"doc.pubyear": {"2000"}},
+		Selections: TTSelections{"doc.pubyear": {Range: &NumRange{From: intPtr(1990)}}},
+	}
+	assert.ErrorContains(t, setup.Validate(), "doc.pubyear is defined in both textTypes and selections")
+}
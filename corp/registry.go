@@ -0,0 +1,71 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Registry is a collection of corpus configurations identified
+// by their IDs. Typically, it is created by LoadDir.
+type Registry struct {
+	setups map[string]*CorpusSetup
+
+	// defined contains keys explicitly defined in configuration
+	// files of the setups (see LoadDir and Resolve)
+	defined map[string]map[string]bool
+}
+
+// Get returns a configuration of a corpus. The second returned
+// value is false for unknown corpora.
+func (r *Registry) Get(id string) (*CorpusSetup, bool) {
+	ans, ok := r.setups[id]
+	return ans, ok
+}
+
+// IDs returns sorted identifiers of all the configured corpora
+func (r *Registry) IDs() []string {
+	ans := make([]string, 0, len(r.setups))
+	for k := range r.setups {
+		ans = append(ans, k)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
+// Add adds a corpus configuration. Setups with an empty or already
+// registered ID are rejected. Please note that the setup is not
+// validated here (see CorpusSetup.Validate).
+func (r *Registry) Add(setup *CorpusSetup) error {
+	if setup.ID == "" {
+		return fmt.Errorf("cannot add corpus setup with empty ID")
+	}
+	if _, ok := r.setups[setup.ID]; ok {
+		return fmt.Errorf("duplicate corpus setup %s", setup.ID)
+	}
+	r.setups[setup.ID] = setup
+	return nil
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		setups:  make(map[string]*CorpusSetup),
+		defined: make(map[string]map[string]bool),
+	}
+}
@@ -16,6 +16,13 @@
 
 package corp
 
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"slices"
+	"sort"
+)
+
 const (
 	TextPropertyAuthor      = "author"
 	TextPropertyTitle       = "title"
@@ -36,6 +43,12 @@ func (tp TextProperty) Validate() bool {
 		tp == TextPropertyTranslator || tp == TextPropertyOriginaLang
 }
 
+// IsNumeric tests whether values of the property are numbers
+// (e.g. it can be selected using numeric ranges)
+func (tp TextProperty) IsNumeric() bool {
+	return tp == TextPropertyPubYear
+}
+
 func (tp TextProperty) String() string {
 	return string(tp)
 }
@@ -64,8 +77,199 @@ func (s StructAttr) IsZero() bool {
 
 // -------------
 
+// TextTypes is a selection of texts based on structural attributes
+// (e.g. "doc.txtype" -> ["FIC: novels", "NMG: magazines"]). Values of
+// a single attribute are combined using OR, attributes using AND.
+// An attribute not present in the map (or with no values) does not
+// constrain the selection at all.
 type TextTypes map[string][]string
 
+func uniqSortedValues(values []string) []string {
+	ans := append([]string{}, values...)
+	sort.Strings(ans)
+	return slices.Compact(ans)
+}
+
+// Canonical returns a copy with sorted and deduplicated values.
+// Attributes with no values are removed.
+func (tt TextTypes) Canonical() TextTypes {
+	ans := make(TextTypes, len(tt))
+	for k, v := range tt {
+		if len(v) > 0 {
+			ans[k] = uniqSortedValues(v)
+		}
+	}
+	return ans
+}
+
+// Attrs returns sorted names of attributes with at least one value
+func (tt TextTypes) Attrs() []string {
+	ans := make([]string, 0, len(tt))
+	for k, v := range tt {
+		if len(v) > 0 {
+			ans = append(ans, k)
+		}
+	}
+	sort.Strings(ans)
+	return ans
+}
+
+// Equal tests whether both selections are the same
+// (regardless of value order and duplicities)
+func (tt TextTypes) Equal(other TextTypes) bool {
+	c1, c2 := tt.Canonical(), other.Canonical()
+	if len(c1) != len(c2) {
+		return false
+	}
+	for k, v := range c1 {
+		if !slices.Equal(v, c2[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a stable hash of the canonical form of the selection.
+// Equal selections produce the same hash so it can be used e.g.
+// as a cache key.
+func (tt TextTypes) Hash() string {
+	c := tt.Canonical()
+	h := sha1.New()
+	for _, attr := range c.Attrs() {
+		h.Write([]byte(attr))
+		for _, v := range c[attr] {
+			h.Write([]byte{0})
+			h.Write([]byte(v))
+		}
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// valuesDiff returns values of `a` not present in `b`
+func valuesDiff(a, b []string) []string {
+	ans := make([]string, 0, len(a))
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			ans = append(ans, v)
+		}
+	}
+	return ans
+}
+
+// Intersect returns a selection of texts matching both selections.
+// For attributes present in both operands, the values are intersected.
+// Attributes present in only one operand are kept as they are.
+// The second returned value is false (and the selection is nil) in case
+// the intersection of some attribute values is empty, i.e. no text can
+// match. Please note that such a result cannot be expressed as TextTypes
+// as an empty selection means "all texts".
+func (tt TextTypes) Intersect(other TextTypes) (TextTypes, bool) {
+	ans := tt.Canonical()
+	for attr, values := range other.Canonical() {
+		curr, ok := ans[attr]
+		if !ok {
+			ans[attr] = values
+			continue
+		}
+		common := make([]string, 0, len(curr))
+		for _, v := range curr {
+			if slices.Contains(values, v) {
+				common = append(common, v)
+			}
+		}
+		if len(common) == 0 {
+			return nil, false
+		}
+		ans[attr] = common
+	}
+	return ans, true
+}
+
+// Union returns the narrowest selection containing texts of both selections.
+// For attributes present in both operands, the values are merged.
+// Attributes present in only one operand are removed as the other
+// operand does not constrain them. Please note that the result may
+// contain more texts than both the operands combined (e.g. a union of
+// {a: [1], b: [1]} and {a: [2], b: [2]} is {a: [1, 2], b: [1, 2]}).
+func (tt TextTypes) Union(other TextTypes) TextTypes {
+	c1, c2 := tt.Canonical(), other.Canonical()
+	ans := make(TextTypes, len(c1))
+	for attr, values := range c1 {
+		if values2, ok := c2[attr]; ok {
+			ans[attr] = uniqSortedValues(append(values, values2...))
+		}
+	}
+	return ans
+}
+
+// Subtract returns a selection of texts matching the selection but not
+// matching `other` (i.e. the set difference of the matching texts).
+// As TextTypes can express only "products" of attribute values,
+// the exact difference is representable only in these cases:
+//
+//   - the selections are disjoint (some shared attribute has no common
+//     values) - the selection is returned unchanged,
+//   - `other` cuts the selection in a single attribute - i.e. for all
+//     the other attributes of `other`, the selection is constrained to
+//     a subset of `other` values - the values of `other` are removed from
+//     the attribute (the attribute must be constrained by the selection
+//     as there is no way to express "all values except").
+//
+// In all the other cases (including an empty result; see IsSubsetOf),
+// the second returned value is false and the selection is nil.
+func (tt TextTypes) Subtract(other TextTypes) (TextTypes, bool) {
+	ans, sub := tt.Canonical(), other.Canonical()
+	var cutAttr string
+	var numCuts int
+	for attr, values := range sub {
+		curr, ok := ans[attr]
+		if !ok {
+			// the selection contains values outside of `other`
+			cutAttr = attr
+			numCuts++
+			continue
+		}
+		rest := valuesDiff(curr, values)
+		if len(rest) == len(curr) {
+			return ans, true
+		}
+		if len(rest) > 0 {
+			cutAttr = attr
+			numCuts++
+		}
+	}
+	if numCuts != 1 {
+		return nil, false
+	}
+	curr, ok := ans[cutAttr]
+	if !ok {
+		return nil, false
+	}
+	ans[cutAttr] = valuesDiff(curr, sub[cutAttr])
+	return ans, true
+}
+
+// IsSubsetOf tests whether all the texts matching the selection also
+// match `other`, i.e. each attribute constrained by `other` is constrained
+// by the selection too with a subset of values. An empty selection
+// (all texts) is a subset only of an empty selection.
+func (tt TextTypes) IsSubsetOf(other TextTypes) bool {
+	c1 := tt.Canonical()
+	for attr, values := range other.Canonical() {
+		curr, ok := c1[attr]
+		if !ok {
+			return false
+		}
+		for _, v := range curr {
+			if !slices.Contains(values, v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // ------
 
 type TTPropertyConf struct {
@@ -120,7 +324,27 @@ func (ttp TextTypeProperties) Attr(prop TextProperty) string {
 // Subcorpus represents a subcorpus created by selecting specific
 // values out of different structural attributes.
 type Subcorpus struct {
-	ID          string            `json:"id"`
-	TextTypes   TextTypes         `json:"textTypes"`
+	ID string `json:"id"`
+
+	// TextTypes is a plain selection of attribute values
+	TextTypes TextTypes `json:"textTypes"`
+
+	// Selections contains extended selections (regular expressions,
+	// numeric ranges, negation). They are combined with TextTypes
+	// using AND, but a single attribute should not be present in both
+	// (see CorpusSetup.Validate).
+	Selections TTSelections `json:"selections,omitempty"`
+
 	Description map[string]string `json:"description"`
 }
+
+// AllSelections returns both TextTypes and Selections as a single
+// TTSelections value. In case an attribute is present in both,
+// the extended selection is used.
+func (s Subcorpus) AllSelections() TTSelections {
+	ans := s.TextTypes.Selections()
+	for k, v := range s.Selections {
+		ans[k] = v
+	}
+	return ans
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextTypesCanonicalAndHash(t *testing.T) {
+	tt := TextTypes{"doc.txtype": {"B", "A", "B"}, "doc.id": {}}
+	assert.Equal(t, TextTypes{"doc.txtype": {"A", "B"}}, tt.Canonical())
+	assert.Equal(t, []string{"B", "A", "B"}, tt["doc.txtype"])
+	assert.True(t, tt.Equal(TextTypes{"doc.txtype": {"A", "B"}}))
+	assert.False(t, tt.Equal(TextTypes{"doc.txtype": {"A"}}))
+	assert.Equal(t, tt.Hash(), TextTypes{"doc.txtype": {"A", "B"}}.Hash())
+	assert.NotEqual(t, tt.Hash(), TextTypes{"doc.txtype": {"AB"}}.Hash())
+	assert.NotEqual(t, tt.Hash(), TextTypes{"doc.txtyp": {"eA", "B"}}.Hash())
+	assert.Len(t, tt.Hash(), 40)
+}
+
+func TestTextTypesIntersect(t *testing.T) {
+	a := TextTypes{"doc.txtype": {"A", "B"}, "doc.pubyear": {"2000"}}
+	b := TextTypes{"doc.txtype": {"B", "C"}, "text.author": {"X"}}
+	ans, ok := a.Intersect(b)
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		TextTypes{"doc.txtype": {"B"}, "doc.pubyear": {"2000"}, "text.author": {"X"}},
+		ans,
+	)
+	ans, ok = a.Intersect(TextTypes{"doc.txtype": {"C"}})
+	assert.False(t, ok)
+	assert.Nil(t, ans)
+}
+
+func TestTextTypesUnion(t *testing.T) {
+	a := TextTypes{"doc.txtype": {"A", "B"}, "doc.pubyear": {"2000"}}
+	b := TextTypes{"doc.txtype": {"C", "B"}, "text.author": {"X"}}
+	assert.Equal(t, TextTypes{"doc.txtype": {"A", "B", "C"}}, a.Union(b))
+	assert.Equal(t, TextTypes{}, a.Union(TextTypes{}))
+}
+
+func TestTextTypesSubtract(t *testing.T) {
+	a := TextTypes{"doc.txtype": {"A", "B"}, "doc.pubyear": {"2000", "2001"}}
+
+	// a single cut attribute
+	ans, ok := a.Subtract(TextTypes{"doc.txtype": {"B"}})
+	assert.True(t, ok)
+	assert.Equal(t, TextTypes{"doc.txtype": {"A"}, "doc.pubyear": {"2000", "2001"}}, ans)
+
+	// the other attribute of `other` covers the whole selection
+	ans, ok = a.Subtract(TextTypes{"doc.txtype": {"B", "C"}, "doc.pubyear": {"2000", "2001", "2002"}})
+	assert.True(t, ok)
+	assert.Equal(t, TextTypes{"doc.txtype": {"A"}, "doc.pubyear": {"2000", "2001"}}, ans)
+
+	// disjoint selections
+	ans, ok = a.Subtract(TextTypes{"doc.txtype": {"C"}, "text.author": {"X"}})
+	assert.True(t, ok)
+	assert.Equal(t, a.Canonical(), ans)
+
+	// {A,B}x{2000,2001} minus {B}x{2000} is not a product
+	ans, ok = a.Subtract(TextTypes{"doc.txtype": {"B"}, "doc.pubyear": {"2000"}})
+	assert.False(t, ok)
+	assert.Nil(t, ans)
+
+	// texts of other authors with txtype B remain
+	_, ok = a.Subtract(TextTypes{"doc.txtype": {"B"}, "text.author": {"X"}})
+	assert.False(t, ok)
+
+	// "all pubyears except 2000" cannot be expressed
+	_, ok = TextTypes{"doc.txtype": {"A"}}.Subtract(TextTypes{"doc.pubyear": {"2000"}})
+	assert.False(t, ok)
+
+	// empty result
+	ans, ok = a.Subtract(TextTypes{"doc.pubyear": {"2000", "2001"}})
+	assert.False(t, ok)
+	assert.Nil(t, ans)
+	assert.True(t, a.IsSubsetOf(TextTypes{"doc.pubyear": {"2000", "2001"}}))
+	_, ok = a.Subtract(TextTypes{})
+	assert.False(t, ok)
+}
+
+func TestTextTypesIsSubsetOf(t *testing.T) {
+	a := TextTypes{"doc.txtype": {"A"}, "doc.pubyear": {"2000"}}
+	assert.True(t, a.IsSubsetOf(TextTypes{"doc.txtype": {"A", "B"}}))
+	assert.True(t, a.IsSubsetOf(TextTypes{}))
+	assert.True(t, a.IsSubsetOf(a))
+	assert.False(t, a.IsSubsetOf(TextTypes{"doc.txtype": {"B"}}))
+	assert.False(t, a.IsSubsetOf(TextTypes{"text.author": {"X"}}))
+	assert.False(t, TextTypes{}.IsSubsetOf(a))
+}
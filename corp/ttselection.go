@@ -0,0 +1,283 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NumRange is an inclusive range of non-negative integers.
+// A nil bound means the range is open on that side.
+type NumRange struct {
+	From *int `json:"from,omitempty"`
+	To   *int `json:"to,omitempty"`
+}
+
+// Validate tests whether the bounds are non-negative and ordered
+func (nr NumRange) Validate() error {
+	if nr.From == nil && nr.To == nil {
+		return fmt.Errorf("range must have at least one bound")
+	}
+	if nr.From != nil && *nr.From < 0 || nr.To != nil && *nr.To < 0 {
+		return fmt.Errorf("range bounds must be non-negative")
+	}
+	if nr.From != nil && nr.To != nil && *nr.From > *nr.To {
+		return fmt.Errorf("invalid range %d-%d", *nr.From, *nr.To)
+	}
+	return nil
+}
+
+// ------
+
+// TTSelection is a selection of a single structural attribute values.
+// Exactly one of Values, Regexp and Range should be set.
+//
+// In JSON, a plain list of values (e.g. `["A", "B"]`) is accepted too
+// and plain non-negated selections are also written this way so
+// the format is compatible with TextTypes.
+type TTSelection struct {
+
+	// Values is a list of accepted values (matched literally)
+	Values []string `json:"values,omitempty"`
+
+	// Regexp is a regular expression values must match. As in CQL,
+	// the expression must match the whole value (e.g. `Čapek.*` selects
+	// all values starting with "Čapek"). The `^` and `$` anchors are
+	// therefore redundant and a leading `^` and a trailing `$` are
+	// ignored (see UnanchoredRegexp).
+	Regexp string `json:"regexp,omitempty"`
+
+	// Range is a numeric range of accepted values
+	Range *NumRange `json:"range,omitempty"`
+
+	// Negated inverts the selection
+	Negated bool `json:"negated,omitempty"`
+}
+
+type ttSelectionJSON struct {
+	Values  []string  `json:"values,omitempty"`
+	Regexp  string    `json:"regexp,omitempty"`
+	Range   *NumRange `json:"range,omitempty"`
+	Negated bool      `json:"negated,omitempty"`
+}
+
+// IsPlain tests whether the selection is a plain list of values
+// (i.e. it is expressible via TextTypes)
+func (sel TTSelection) IsPlain() bool {
+	return sel.Regexp == "" && sel.Range == nil && !sel.Negated
+}
+
+// Validate tests whether exactly one kind of selection is defined
+// and whether it is valid. The `numeric` argument specifies whether
+// the respective attribute is numeric (ranges are accepted only for
+// numeric attributes). An empty plain selection is considered valid
+// (it does not constrain the attribute).
+func (sel TTSelection) Validate(numeric bool) error {
+	if sel.IsPlain() && len(sel.Values) == 0 {
+		return nil
+	}
+	var defined int
+	if len(sel.Values) > 0 {
+		defined++
+	}
+	if sel.Regexp != "" {
+		defined++
+		if _, err := sel.compileRegexp(); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
+	if sel.Range != nil {
+		defined++
+		if !numeric {
+			return fmt.Errorf("range cannot be applied to a non-numeric attribute")
+		}
+		if err := sel.Range.Validate(); err != nil {
+			return err
+		}
+	}
+	if defined != 1 {
+		return fmt.Errorf("exactly one of values, regexp and range must be defined")
+	}
+	return nil
+}
+
+// hasEndAnchor tests whether the expression ends with an unescaped `$`
+func hasEndAnchor(rx string) bool {
+	if !strings.HasSuffix(rx, "$") {
+		return false
+	}
+	trimmed := strings.TrimRight(rx[:len(rx)-1], `\`)
+	return (len(rx)-1-len(trimmed))%2 == 0
+}
+
+// UnanchoredRegexp returns Regexp without a leading `^` and
+// a trailing `$` (the expression always matches whole values)
+func (sel TTSelection) UnanchoredRegexp() string {
+	ans := strings.TrimPrefix(sel.Regexp, "^")
+	if hasEndAnchor(ans) {
+		ans = ans[:len(ans)-1]
+	}
+	return ans
+}
+
+func (sel TTSelection) compileRegexp() (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + sel.UnanchoredRegexp() + ")$")
+}
+
+// Compile prepares the selection for matching of many values
+// (a regular expression is compiled just once).
+func (sel TTSelection) Compile() (*TTMatcher, error) {
+	ans := &TTMatcher{sel: sel}
+	if sel.Range == nil && sel.Regexp != "" {
+		rx, err := sel.compileRegexp()
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		ans.rx = rx
+	}
+	return ans, nil
+}
+
+// Matches tests whether a value is selected (see TTMatcher.Matches).
+// The selection is compiled on each call so for testing multiple values,
+// Compile should be used instead. Invalid regular expressions do not
+// match anything.
+func (sel TTSelection) Matches(value string) bool {
+	m, err := sel.Compile()
+	if err != nil {
+		return false
+	}
+	return m.Matches(value)
+}
+
+func (sel TTSelection) MarshalJSON() ([]byte, error) {
+	if sel.IsPlain() {
+		values := sel.Values
+		if values == nil {
+			values = []string{}
+		}
+		return json.Marshal(values)
+	}
+	return json.Marshal(ttSelectionJSON(sel))
+}
+
+func (sel *TTSelection) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		*sel = TTSelection{}
+		return json.Unmarshal(trimmed, &sel.Values)
+	}
+	var tmp ttSelectionJSON
+	if err := json.Unmarshal(trimmed, &tmp); err != nil {
+		return err
+	}
+	*sel = TTSelection(tmp)
+	return nil
+}
+
+// ------
+
+// TTMatcher is a compiled TTSelection
+type TTMatcher struct {
+	sel TTSelection
+	rx  *regexp.Regexp
+}
+
+// Matches tests whether a value is selected. Regular expressions
+// must match the whole value. Numeric ranges select only non-negative
+// integers without leading zeros. An empty plain selection matches
+// everything.
+func (m *TTMatcher) Matches(value string) bool {
+	var ans bool
+	switch {
+	case m.sel.Range != nil:
+		n, err := strconv.Atoi(value)
+		ans = err == nil && n >= 0 && strconv.Itoa(n) == value &&
+			(m.sel.Range.From == nil || n >= *m.sel.Range.From) &&
+			(m.sel.Range.To == nil || n <= *m.sel.Range.To)
+	case m.rx != nil:
+		ans = m.rx.MatchString(value)
+	case len(m.sel.Values) == 0:
+		return true
+	default:
+		ans = slices.Contains(m.sel.Values, value)
+	}
+	return ans != m.sel.Negated
+}
+
+// ------
+
+// ------
+
+// TTSelections is an extended version of TextTypes supporting
+// also regular expressions, numeric ranges and negation. Selections
+// of individual attributes are combined using AND.
+type TTSelections map[string]TTSelection
+
+// Selections converts text types to TTSelections
+func (tt TextTypes) Selections() TTSelections {
+	ans := make(TTSelections, len(tt))
+	for k, v := range tt {
+		ans[k] = TTSelection{Values: append([]string{}, v...)}
+	}
+	return ans
+}
+
+// TextTypes converts the selections to plain text types. The second
+// returned value is false in case some of the selections is not plain
+// (see TTSelection.IsPlain).
+func (s TTSelections) TextTypes() (TextTypes, bool) {
+	ans := make(TextTypes, len(s))
+	for k, v := range s {
+		if !v.IsPlain() {
+			return nil, false
+		}
+		ans[k] = append([]string{}, v.Values...)
+	}
+	return ans, true
+}
+
+// Attrs returns sorted names of the selected attributes
+func (s TTSelections) Attrs() []string {
+	ans := make([]string, 0, len(s))
+	for k := range s {
+		ans = append(ans, k)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
+// Validate validates all the selections. Attribute kinds are
+// derived from `props` - only attributes mapped to numeric
+// properties (see TextProperty.IsNumeric) accept ranges.
+func (s TTSelections) Validate(props TextTypeProperties) error {
+	errs := make([]error, 0, len(s))
+	for _, attr := range s.Attrs() {
+		if err := s[attr].Validate(props.Prop(attr).IsNumeric()); err != nil {
+			errs = append(errs, fmt.Errorf("invalid selection of %s: %w", attr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestTTSelectionsJSON(t *testing.T) {
+	src := `{
+		"doc.txtype": ["A", "B"],
+		"doc.pubyear": {"range": {"from": 1990, "to": 2005}},
+		"text.author": {"regexp": "Čapek.*", "negated": true}
+	}`
+	var sels TTSelections
+	assert.NoError(t, json.Unmarshal([]byte(src), &sels))
+	assert.Equal(t, TTSelections{
+		"doc.txtype":  {Values: []string{"A", "B"}},
+		"doc.pubyear": {Range: &NumRange{From: intPtr(1990), To: intPtr(2005)}},
+		"text.author": {Regexp: "Čapek.*", Negated: true},
+	}, sels)
+
+	data, err := json.Marshal(sels)
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t,
+		`{"doc.txtype": ["A", "B"], "doc.pubyear": {"range": {"from": 1990, "to": 2005}},
+		"text.author": {"regexp": "Čapek.*", "negated": true}}`,
+		string(data),
+	)
+
+	_, ok := sels.TextTypes()
+	assert.False(t, ok)
+}
+
+func TestTTSelectionsValidate(t *testing.T) {
+	props := TextTypeProperties{
+		TextPropertyPubYear:  {Name: "doc.pubyear"},
+		TextPropertyTextType: {Name: "doc.txtype"},
+	}
+	assert.NoError(t, TTSelections{
+		"doc.pubyear": {Range: &NumRange{From: intPtr(1990)}},
+		"doc.txtype":  {Values: []string{"A"}, Negated: true},
+		"doc.id":      {},
+	}.Validate(props))
+
+	for _, sel := range []TTSelection{
+		{Range: &NumRange{From: intPtr(2000), To: intPtr(1990)}},
+		{Range: &NumRange{}},
+		{Range: &NumRange{From: intPtr(-1)}},
+		{Regexp: "(", Negated: true},
+		{Values: []string{"1990"}, Regexp: "19.*"},
+		{Negated: true},
+	} {
+		assert.Error(t, TTSelections{"doc.pubyear": sel}.Validate(props), "%+v", sel)
+	}
+	err := TTSelections{"doc.txtype": {Range: &NumRange{From: intPtr(1)}}}.Validate(props)
+	assert.ErrorContains(t, err, "non-numeric")
+}
+
+func TestSubcorpusSelections(t *testing.T) {
+	var sub Subcorpus
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"id": "x",
+		"textTypes": {"doc.txtype": ["A", "B"]},
+		"selections": {"doc.pubyear": {"range": {"from": 2000}}}
+	}`), &sub))
+	assert.Equal(t, TextTypes{"doc.txtype": {"A", "B"}}, sub.TextTypes)
+	assert.Equal(t, TTSelections{
+		"doc.txtype":  {Values: []string{"A", "B"}},
+		"doc.pubyear": {Range: &NumRange{From: intPtr(2000)}},
+	}, sub.AllSelections())
+
+	// plain text types keep working with the set operations
+	ans, ok := sub.TextTypes.Intersect(TextTypes{"doc.txtype": {"B", "C"}})
+	assert.True(t, ok)
+	assert.Equal(t, TextTypes{"doc.txtype": {"B"}}, ans)
+
+	data, err := json.Marshal(Subcorpus{ID: "y", TextTypes: TextTypes{"doc.id": {"1"}}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "y", "textTypes": {"doc.id": ["1"]}, "description": null}`, string(data))
+}
+
+func TestTTSelectionMatches(t *testing.T) {
+	author := TTSelection{Regexp: "Čapek.*"}
+	assert.True(t, author.Matches("Čapek, K."))
+	assert.False(t, author.Matches("K. Čapek"))
+	assert.False(t, TTSelection{Regexp: "Čapek"}.Matches("Čapek, K."))
+	assert.True(t, TTSelection{Regexp: "FIC.*", Negated: true}.Matches("NMG: news"))
+
+	years := TTSelection{Range: &NumRange{From: intPtr(1990), To: intPtr(2005)}}
+	assert.True(t, years.Matches("1990"))
+	assert.False(t, years.Matches("01990"))
+	assert.False(t, years.Matches("2006"))
+
+	assert.True(t, TTSelection{Values: []string{"A"}}.Matches("A"))
+	assert.False(t, TTSelection{Values: []string{"A"}, Negated: true}.Matches("A"))
+	assert.True(t, TTSelection{}.Matches("anything"))
+	assert.False(t, TTSelection{Regexp: "("}.Matches("("))
+}
+
+func TestTTSelectionCompile(t *testing.T) {
+	m, err := TTSelection{Regexp: "Čapek.*", Negated: true}.Compile()
+	assert.NoError(t, err)
+	assert.False(t, m.Matches("Čapek, K."))
+	assert.True(t, m.Matches("K. Čapek"))
+
+	m, err = TTSelection{Values: []string{"A", "B"}}.Compile()
+	assert.NoError(t, err)
+	assert.True(t, m.Matches("B"))
+	assert.False(t, m.Matches("C"))
+
+	_, err = TTSelection{Regexp: "("}.Compile()
+	assert.ErrorContains(t, err, "invalid regular expression")
+}
+
+func TestTTSelectionAnchors(t *testing.T) {
+	for _, rx := range []string{"^Čapek.*", "Čapek.*$", "^Čapek.*$"} {
+		sel := TTSelection{Regexp: rx}
+		assert.NoError(t, sel.Validate(false))
+		assert.Equal(t, "Čapek.*", sel.UnanchoredRegexp())
+		assert.True(t, sel.Matches("Čapek Karel"))
+		assert.False(t, sel.Matches("Karel Čapek"))
+	}
+	assert.False(t, TTSelection{Regexp: "^Čapek"}.Matches("Čapek Karel"))
+	assert.Equal(t, `US\$`, TTSelection{Regexp: `US\$`}.UnanchoredRegexp())
+	assert.True(t, TTSelection{Regexp: `US\$`}.Matches("US$"))
+	assert.Equal(t, `US\\`, TTSelection{Regexp: `US\\$`}.UnanchoredRegexp())
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+)
+
+// findVariant searches for a variant by its ID. In case there is
+// no such variant, the value is used as a key of the Variants map.
+// Variants are searched in the order of their keys so the result
+// is stable even for setups with duplicate variant IDs (which are
+// reported by Validate).
+func (cs *CorpusSetup) findVariant(id string) (CorpusVariant, bool) {
+	for _, k := range sortedKeys(cs.Variants) {
+		if v := cs.Variants[k]; v.ID == id {
+			return v, true
+		}
+	}
+	v, ok := cs.Variants[id]
+	return v, ok
+}
+
+func (cs *CorpusSetup) variantSetup(v CorpusVariant) *CorpusSetup {
+	ans := cs.clone()
+	ans.ID = v.ID
+	ans.Extends = ""
+	if len(v.FullName) > 0 {
+		ans.FullName = maps.Clone(v.FullName)
+	}
+	if len(v.Description) > 0 {
+		ans.Description = maps.Clone(v.Description)
+	}
+	ans.Variants = nil
+	return ans
+}
+
+// VariantSetup creates a standalone setup of a corpus variant specified
+// either by its ID (e.g. `intercorp_v16_en`) or by its key in Variants.
+// The ID is taken from the variant, localized names and descriptions
+// defined by the variant replace the whole ones of the core setup (i.e.
+// languages are not inherited) and all the other values are copied
+// from the core setup the same way CorpusSetup.Extend does. The returned
+// setup has no variants.
+func (cs *CorpusSetup) VariantSetup(id string) (*CorpusSetup, error) {
+	v, ok := cs.findVariant(id)
+	if !ok {
+		return nil, fmt.Errorf("corpus %s has no variant %s", cs.ID, id)
+	}
+	if v.ID == "" {
+		return nil, fmt.Errorf("variant %s of corpus %s has no ID", id, cs.ID)
+	}
+	return cs.variantSetup(v), nil
+}
+
+// VariantSetups creates standalone setups of all the variants
+// (see VariantSetup) sorted by their IDs. Variants without ID
+// are skipped.
+func (cs *CorpusSetup) VariantSetups() []*CorpusSetup {
+	ans := make([]*CorpusSetup, 0, len(cs.Variants))
+	for _, v := range cs.Variants {
+		if v.ID != "" {
+			ans = append(ans, cs.variantSetup(v))
+		}
+	}
+	sort.Slice(ans, func(i, j int) bool { return ans[i].ID < ans[j].ID })
+	return ans
+}
+
+// Variant searches all the stored setups for a variant with the
+// specified ID and returns its standalone setup (see
+// CorpusSetup.VariantSetup). In case more setups define the same
+// variant, the first one in alphabetical order is used.
+func (r *Registry) Variant(id string) (*CorpusSetup, bool) {
+	for _, setupID := range r.IDs() {
+		setup := r.setups[setupID]
+		for _, k := range sortedKeys(setup.Variants) {
+			if v := setup.Variants[k]; v.ID == id {
+				return setup.variantSetup(v), true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package corp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intercorpSetup() *CorpusSetup {
+	return &CorpusSetup{
+		ID:             "intercorp_v16",
+		FullName:       map[string]string{"en": "InterCorp v16", "cs": "InterCorp v16"},
+		Description:    map[string]string{"en": "parallel corpus"},
+		MaximumRecords: 50,
+		PosAttrs:       PosAttrList{{Name: "word"}, {Name: "lemma"}},
+		Subcorpora:     map[string]Subcorpus{"fic": {ID: "fic"}},
+		Variants: map[string]CorpusVariant{
+			"en": {
+				ID:       "intercorp_v16_en",
+				FullName: map[string]string{"en": "InterCorp v16 - English"},
+			},
+			"cs": {
+				ID:          "intercorp_v16_cs",
+				FullName:    map[string]string{"cs": "InterCorp v16 - čeština"},
+				Description: map[string]string{"en": "Czech part"},
+			},
+			"xx": {},
+		},
+	}
+}
+
+func TestVariantSetup(t *testing.T) {
+	setup := intercorpSetup()
+	ans, err := setup.VariantSetup("intercorp_v16_en")
+	assert.NoError(t, err)
+	assert.Equal(t, "intercorp_v16_en", ans.ID)
+	assert.Equal(t, map[string]string{"en": "InterCorp v16 - English"}, ans.FullName)
+	assert.Equal(t, map[string]string{"en": "parallel corpus"}, ans.Description)
+	assert.Equal(t, 50, ans.MaximumRecords)
+	assert.Nil(t, ans.Variants)
+	assert.Len(t, setup.Variants, 3)
+	assert.Equal(t, "InterCorp v16", setup.FullName["en"])
+
+	// the variant does not share data with the core setup
+	ans.PosAttrs[0].Name = "foo"
+	ans.Description["cs"] = "paralelní korpus"
+	delete(ans.Subcorpora, "fic")
+	assert.Equal(t, "word", setup.PosAttrs[0].Name)
+	assert.Equal(t, map[string]string{"en": "parallel corpus"}, setup.Description)
+	assert.Len(t, setup.Subcorpora, 1)
+
+	ans, err = setup.VariantSetup("cs")
+	assert.NoError(t, err)
+	assert.Equal(t, "intercorp_v16_cs", ans.ID)
+	assert.Equal(t, "Czech part", ans.LocaleDescription("cs"))
+
+	_, err = setup.VariantSetup("xx")
+	assert.Error(t, err)
+	_, err = setup.VariantSetup("intercorp_v16_de")
+	assert.Error(t, err)
+}
+
+func TestVariantSetupDuplicateID(t *testing.T) {
+	setup := intercorpSetup()
+	setup.Variants["cs2"] = CorpusVariant{ID: "intercorp_v16_cs", Description: map[string]string{"en": "other"}}
+	for i := 0; i < 10; i++ {
+		ans, err := setup.VariantSetup("intercorp_v16_cs")
+		assert.NoError(t, err)
+		assert.Equal(t, "Czech part", ans.LocaleDescription("en"))
+	}
+	err := setup.Validate()
+	assert.ErrorContains(t, err, "variants cs and cs2 have the same ID intercorp_v16_cs")
+	assert.ErrorContains(t, err, "variant xx has no ID")
+}
+
+func TestVariantSetups(t *testing.T) {
+	ans := intercorpSetup().VariantSetups()
+	if assert.Len(t, ans, 2) {
+		assert.Equal(t, "intercorp_v16_cs", ans[0].ID)
+		assert.Equal(t, "intercorp_v16_en", ans[1].ID)
+	}
+}
+
+func TestRegistryVariant(t *testing.T) {
+	reg := NewRegistry()
+	assert.NoError(t, reg.Add(intercorpSetup()))
+	assert.NoError(t, reg.Add(&CorpusSetup{ID: "intercorp_*", MaximumRecords: 1}))
+	ans, ok := reg.Variant("intercorp_v16_en")
+	assert.True(t, ok)
+	assert.Equal(t, "intercorp_v16_en", ans.ID)
+	_, ok = reg.Variant("en")
+	assert.False(t, ok)
+
+	ans, ok = reg.Lookup("intercorp_v16_cs")
+	assert.True(t, ok)
+	assert.Equal(t, 50, ans.MaximumRecords)
+	ans, ok = reg.Lookup("intercorp_v16_de")
+	assert.True(t, ok)
+	assert.Equal(t, 1, ans.MaximumRecords)
+}
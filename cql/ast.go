@@ -0,0 +1,462 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cql provides an abstract syntax tree, a parser and a printer
+// for the Manatee Corpus Query Language (CQL).
+//
+// All the positions stored in the tree nodes are zero-based
+// rune offsets within the original query string.
+package cql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a common interface of all the syntax tree nodes.
+// String returns the node in the canonical CQL form.
+type Node interface {
+	String() string
+}
+
+// AttrExpr is a boolean expression over attribute tests
+// as used within token queries (`[...]`) and structures (`<doc ...>`)
+type AttrExpr interface {
+	Node
+	attrExpr()
+}
+
+// SeqItem is an item of a sequence (a token query, a group
+// or a structure)
+type SeqItem interface {
+	Node
+	seqItem()
+}
+
+// ------
+
+// Operator is a comparison operator of an attribute test
+type Operator string
+
+const (
+	// OpEq matches values against a regular expression
+	OpEq Operator = "="
+
+	// OpNotEq is a negation of OpEq
+	OpNotEq Operator = "!="
+
+	// OpLiteralEq matches values literally (no regular expression)
+	OpLiteralEq Operator = "=="
+
+	// OpNotLiteralEq is a negation of OpLiteralEq
+	OpNotLiteralEq Operator = "!=="
+)
+
+// Validate tests whether the operator is supported
+func (op Operator) Validate() error {
+	if op == OpEq || op == OpNotEq || op == OpLiteralEq || op == OpNotLiteralEq {
+		return nil
+	}
+	return fmt.Errorf("invalid CQL operator `%s`", op)
+}
+
+// IsNegative tests whether the operator is a negated one
+func (op Operator) IsNegative() bool {
+	return op == OpNotEq || op == OpNotLiteralEq
+}
+
+// Quote creates a CQL string literal from a regular expression.
+// Existing escape sequences are kept, unescaped double quotes are escaped.
+func Quote(value string) string {
+	var ans strings.Builder
+	ans.WriteString(`"`)
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes):
+			ans.WriteRune(runes[i])
+			ans.WriteRune(runes[i+1])
+			i++
+		case runes[i] == '\\':
+			ans.WriteString(`\\`)
+		case runes[i] == '"':
+			ans.WriteString(`\"`)
+		default:
+			ans.WriteRune(runes[i])
+		}
+	}
+	ans.WriteString(`"`)
+	return ans.String()
+}
+
+// ------
+
+// AttrTest compares a positional or structural attribute
+// with a value (`lemma="dům"`). An empty Attr means the default
+// attribute of the corpus (used for bare strings like `"dům"`).
+type AttrTest struct {
+
+	// Pos is the position of the attribute name (or of the value
+	// in case of the default attribute)
+	Pos int
+
+	Attr string
+
+	Op Operator
+
+	// ValuePos is the position of the value literal
+	ValuePos int
+
+	// Value is the regular expression (or literal value in case of
+	// OpLiteralEq and OpNotLiteralEq) as written in the query, i.e.
+	// without the quotes but with all the escape sequences
+	Value string
+}
+
+func (t *AttrTest) attrExpr() {}
+
+func (t *AttrTest) String() string {
+	return t.Attr + string(t.Op) + Quote(t.Value)
+}
+
+// AttrAnd is a conjunction of attribute expressions (`a="x" & b="y"`)
+type AttrAnd struct {
+	Pos   int
+	Items []AttrExpr
+}
+
+func (a *AttrAnd) attrExpr() {}
+
+func (a *AttrAnd) String() string {
+	items := make([]string, len(a.Items))
+	for i, v := range a.Items {
+		if _, ok := v.(*AttrOr); ok {
+			items[i] = "(" + v.String() + ")"
+
+		} else {
+			items[i] = v.String()
+		}
+	}
+	return strings.Join(items, " & ")
+}
+
+// AttrOr is a disjunction of attribute expressions (`a="x" | b="y"`)
+type AttrOr struct {
+	Pos   int
+	Items []AttrExpr
+}
+
+func (a *AttrOr) attrExpr() {}
+
+func (a *AttrOr) String() string {
+	items := make([]string, len(a.Items))
+	for i, v := range a.Items {
+		items[i] = v.String()
+	}
+	return strings.Join(items, " | ")
+}
+
+// AttrNot is a negation of an attribute expression (`!a="x"`)
+type AttrNot struct {
+	Pos int
+	Arg AttrExpr
+}
+
+func (a *AttrNot) attrExpr() {}
+
+func (a *AttrNot) String() string {
+	switch a.Arg.(type) {
+	case *AttrAnd, *AttrOr:
+		return "!(" + a.Arg.String() + ")"
+	}
+	return "!" + a.Arg.String()
+}
+
+// ------
+
+// Repetition specifies how many times an item repeats. Max == -1
+// means no upper limit.
+type Repetition struct {
+	Min int
+	Max int
+}
+
+func (r Repetition) String() string {
+	switch {
+	case r.Min == 0 && r.Max == -1:
+		return "*"
+	case r.Min == 1 && r.Max == -1:
+		return "+"
+	case r.Min == 0 && r.Max == 1:
+		return "?"
+	case r.Min == r.Max:
+		return fmt.Sprintf("{%d}", r.Min)
+	case r.Max == -1:
+		return fmt.Sprintf("{%d,}", r.Min)
+	}
+	return fmt.Sprintf("{%d,%d}", r.Min, r.Max)
+}
+
+// TokenQuery matches a single token (`[lemma="dům"]`, `"dům"`
+// or `[]` for any token).
+type TokenQuery struct {
+	Pos int
+
+	// Label is an optional label of the position (`1:[...]`)
+	Label string
+
+	// Expr is the condition; nil means any token
+	Expr AttrExpr
+
+	Repeat *Repetition
+}
+
+func (tq *TokenQuery) seqItem() {}
+
+func (tq *TokenQuery) String() string {
+	var ans strings.Builder
+	if tq.Label != "" {
+		ans.WriteString(tq.Label + ":")
+	}
+	if t, ok := tq.Expr.(*AttrTest); ok && t.Attr == "" && t.Op == OpEq {
+		ans.WriteString(Quote(t.Value))
+
+	} else if tq.Expr != nil {
+		ans.WriteString("[" + tq.Expr.String() + "]")
+
+	} else {
+		ans.WriteString("[]")
+	}
+	if tq.Repeat != nil {
+		ans.WriteString(tq.Repeat.String())
+	}
+	return ans.String()
+}
+
+// Group is a parenthesized query part (`([] "a")+`)
+type Group struct {
+	Pos    int
+	Query  *Alternatives
+	Repeat *Repetition
+}
+
+func (g *Group) seqItem() {}
+
+func (g *Group) String() string {
+	ans := "(" + g.Query.String() + ")"
+	if g.Repeat != nil {
+		ans += g.Repeat.String()
+	}
+	return ans
+}
+
+// StructureKind specifies which part of a structure is matched
+type StructureKind int
+
+const (
+	// StructWhole matches the whole structure (`<s/>`)
+	StructWhole StructureKind = iota
+
+	// StructStart matches the beginning of the structure (`<s>`)
+	StructStart
+
+	// StructEnd matches the end of the structure (`</s>`)
+	StructEnd
+)
+
+// Structure matches a structure boundary or a whole structure,
+// optionally constrained by its attributes (`<doc txtype="FIC"/>`).
+type Structure struct {
+
+	// Pos is the position of the structure name
+	Pos  int
+	Name string
+	Kind StructureKind
+
+	// Expr is an optional condition on structural attributes
+	Expr AttrExpr
+}
+
+func (s *Structure) seqItem() {}
+
+func (s *Structure) String() string {
+	var ans strings.Builder
+	ans.WriteString("<")
+	if s.Kind == StructEnd {
+		ans.WriteString("/")
+	}
+	ans.WriteString(s.Name)
+	if s.Expr != nil {
+		ans.WriteString(" " + s.Expr.String())
+	}
+	if s.Kind == StructWhole {
+		ans.WriteString("/")
+	}
+	ans.WriteString(">")
+	return ans.String()
+}
+
+// ------
+
+// Sequence is a sequence of items matched one after another
+type Sequence struct {
+	Pos   int
+	Items []SeqItem
+}
+
+func (s *Sequence) String() string {
+	items := make([]string, len(s.Items))
+	for i, v := range s.Items {
+		items[i] = v.String()
+	}
+	return strings.Join(items, " ")
+}
+
+// Alternatives is a disjunction of sequences (`"a" "b" | "c"`)
+type Alternatives struct {
+	Pos   int
+	Items []*Sequence
+}
+
+func (a *Alternatives) String() string {
+	items := make([]string, len(a.Items))
+	for i, v := range a.Items {
+		items[i] = v.String()
+	}
+	return strings.Join(items, " | ")
+}
+
+// LabelAttr refers to an attribute of a labeled position (`1.tag`)
+type LabelAttr struct {
+	Pos   int
+	Label string
+	Attr  string
+}
+
+func (la LabelAttr) String() string {
+	return la.Label + "." + la.Attr
+}
+
+// GlobalCondition compares attributes of labeled positions
+// (`& 1.tag = 2.tag`)
+type GlobalCondition struct {
+	Pos   int
+	Left  LabelAttr
+	Op    Operator
+	Right LabelAttr
+}
+
+func (gc *GlobalCondition) String() string {
+	return fmt.Sprintf("%s %s %s", gc.Left, gc.Op, gc.Right)
+}
+
+// WithinClause restricts matches to (or excludes them from) another
+// query's matches - typically structures (`within <s/>`)
+type WithinClause struct {
+	Pos int
+
+	// Containing means the `containing` keyword is used instead of `within`
+	Containing bool
+
+	Negated bool
+
+	Query *Alternatives
+}
+
+func (wc *WithinClause) String() string {
+	var ans strings.Builder
+	if wc.Negated {
+		ans.WriteString("!")
+	}
+	if wc.Containing {
+		ans.WriteString("containing ")
+
+	} else {
+		ans.WriteString("within ")
+	}
+	ans.WriteString(wc.Query.String())
+	return ans.String()
+}
+
+// Query is the root of a parsed CQL query
+type Query struct {
+	Body    *Alternatives
+	Global  []*GlobalCondition
+	Clauses []*WithinClause
+}
+
+// String returns the query in the canonical form
+func (q *Query) String() string {
+	var ans strings.Builder
+	ans.WriteString(q.Body.String())
+	for _, gc := range q.Global {
+		ans.WriteString(" & " + gc.String())
+	}
+	for _, wc := range q.Clauses {
+		ans.WriteString(" " + wc.String())
+	}
+	return ans.String()
+}
+
+// ------
+
+// Walk traverses the tree in depth-first order calling `fn` for each node.
+// In case `fn` returns false, children of the node are skipped.
+func Walk(node Node, fn func(Node) bool) {
+	if !fn(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *Query:
+		Walk(n.Body, fn)
+		for _, gc := range n.Global {
+			Walk(gc, fn)
+		}
+		for _, wc := range n.Clauses {
+			Walk(wc, fn)
+		}
+	case *Alternatives:
+		for _, v := range n.Items {
+			Walk(v, fn)
+		}
+	case *Sequence:
+		for _, v := range n.Items {
+			Walk(v, fn)
+		}
+	case *Group:
+		Walk(n.Query, fn)
+	case *TokenQuery:
+		if n.Expr != nil {
+			Walk(n.Expr, fn)
+		}
+	case *Structure:
+		if n.Expr != nil {
+			Walk(n.Expr, fn)
+		}
+	case *WithinClause:
+		Walk(n.Query, fn)
+	case *AttrAnd:
+		for _, v := range n.Items {
+			Walk(v, fn)
+		}
+	case *AttrOr:
+		for _, v := range n.Items {
+			Walk(v, fn)
+		}
+	case *AttrNot:
+		Walk(n.Arg, fn)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+func pow10(n int) int {
+	ans := 1
+	for i := 0; i < n; i++ {
+		ans *= 10
+	}
+	return ans
+}
+
+// rangeStops splits [min, max] into subranges where each subrange
+// can be expressed by a single pattern (a common prefix followed
+// by one character class and a number of arbitrary digits).
+func rangeStops(min, max int) []int {
+	stops := map[int]bool{max: true}
+	withNines := func(n, nines int) int {
+		return n - n%pow10(nines) + pow10(nines) - 1
+	}
+	for nines := 1; ; nines++ {
+		stop := withNines(min, nines)
+		if stop < min || stop > max {
+			break
+		}
+		stops[stop] = true
+	}
+	for zeros := 1; ; zeros++ {
+		stop := (max + 1) - (max+1)%pow10(zeros) - 1
+		if stop <= min || stop > max {
+			break
+		}
+		stops[stop] = true
+	}
+	ans := make([]int, 0, len(stops))
+	for s := range stops {
+		ans = append(ans, s)
+	}
+	sort.Ints(ans)
+	return ans
+}
+
+// rangePattern creates a pattern for a range where both the bounds
+// have the same number of digits and differ only in a suffix
+// of the form [a-b][0-9]*
+func rangePattern(start, stop int) string {
+	s1, s2 := strconv.Itoa(start), strconv.Itoa(stop)
+	var ans strings.Builder
+	var anyDigits int
+	for i := range s1 {
+		a, b := s1[i], s2[i]
+		switch {
+		case a == b:
+			ans.WriteByte(a)
+		case a == '0' && b == '9':
+			anyDigits++
+		default:
+			fmt.Fprintf(&ans, "[%c-%c]", a, b)
+		}
+	}
+	switch {
+	case anyDigits == 1:
+		ans.WriteString("[0-9]")
+	case anyDigits > 1:
+		fmt.Fprintf(&ans, "[0-9]{%d}", anyDigits)
+	}
+	return ans.String()
+}
+
+// numRangeRegexp creates a regular expression matching decimal
+// representations (without leading zeros) of integers within
+// the range. The upper bound may be nil (no limit).
+func numRangeRegexp(nr corp.NumRange) (string, error) {
+	if err := nr.Validate(); err != nil {
+		return "", err
+	}
+	var min int
+	if nr.From != nil {
+		min = *nr.From
+	}
+	if nr.To != nil {
+		return strings.Join(numRangeItems(min, *nr.To), "|"), nil
+	}
+	// for an open range, we cover all the numbers with the same number
+	// of digits as `min` and then add a pattern for all the longer numbers
+	digits := len(strconv.Itoa(min))
+	items := numRangeItems(min, pow10(digits)-1)
+	items = append(items, fmt.Sprintf("[1-9][0-9]{%d,}", digits))
+	return strings.Join(items, "|"), nil
+}
+
+func numRangeItems(min, max int) []string {
+	ans := make([]string, 0, 10)
+	start := min
+	// ranges crossing a number of digits are split first
+	for digits := len(strconv.Itoa(min)); start <= max; digits++ {
+		end := pow10(digits) - 1
+		if end > max {
+			end = max
+		}
+		for _, stop := range rangeStops(start, end) {
+			ans = append(ans, rangePattern(start, stop))
+			start = stop + 1
+		}
+	}
+	return ans
+}
@@ -0,0 +1,683 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError describes a problem found while parsing
+// a query. Pos is a zero-based position (in runes) within the query.
+type SyntaxError struct {
+	Query string
+	Pos   int
+	Msg   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("CQL syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// Context returns the query with a marker showing where
+// the error occurred. It is intended for user-facing messages.
+func (e *SyntaxError) Context() string {
+	return e.Query + "\n" + strings.Repeat(" ", e.Pos) + "^"
+}
+
+// ------
+
+type tokenType int
+
+const (
+	ttEOF tokenType = iota
+	ttIdent
+	ttString
+	ttLBracket
+	ttRBracket
+	ttLParen
+	ttRParen
+	ttLBrace
+	ttRBrace
+	ttLAngle
+	ttRAngle
+	ttSlash
+	ttPipe
+	ttAmp
+	ttNot
+	ttOp
+	ttColon
+	ttDot
+	ttComma
+	ttRepeat
+)
+
+func (tt tokenType) String() string {
+	switch tt {
+	case ttEOF:
+		return "end of query"
+	case ttIdent:
+		return "identifier"
+	case ttString:
+		return "quoted string"
+	case ttLBracket:
+		return "`[`"
+	case ttRBracket:
+		return "`]`"
+	case ttLParen:
+		return "`(`"
+	case ttRParen:
+		return "`)`"
+	case ttLBrace:
+		return "`{`"
+	case ttRBrace:
+		return "`}`"
+	case ttLAngle:
+		return "`<`"
+	case ttRAngle:
+		return "`>`"
+	case ttSlash:
+		return "`/`"
+	case ttPipe:
+		return "`|`"
+	case ttAmp:
+		return "`&`"
+	case ttNot:
+		return "`!`"
+	case ttOp:
+		return "operator"
+	case ttColon:
+		return "`:`"
+	case ttDot:
+		return "`.`"
+	case ttComma:
+		return "`,`"
+	case ttRepeat:
+		return "repetition operator"
+	}
+	return "unknown"
+}
+
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+func (t token) describe() string {
+	switch t.typ {
+	case ttIdent, ttOp, ttRepeat:
+		return fmt.Sprintf("`%s`", t.value)
+	case ttString:
+		return Quote(t.value)
+	}
+	return t.typ.String()
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+var simpleTokens = map[rune]tokenType{
+	'[': ttLBracket,
+	']': ttRBracket,
+	'(': ttLParen,
+	')': ttRParen,
+	'{': ttLBrace,
+	'}': ttRBrace,
+	'<': ttLAngle,
+	'>': ttRAngle,
+	'/': ttSlash,
+	'|': ttPipe,
+	'&': ttAmp,
+	':': ttColon,
+	'.': ttDot,
+	',': ttComma,
+	'*': ttRepeat,
+	'+': ttRepeat,
+	'?': ttRepeat,
+}
+
+func lex(query string) ([]token, error) {
+	runes := []rune(query)
+	ans := make([]token, 0, len(runes)/2)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if typ, ok := simpleTokens[r]; ok {
+			ans = append(ans, token{typ: typ, value: string(r), pos: i})
+			i++
+			continue
+		}
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '=':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				ans = append(ans, token{typ: ttOp, value: string(OpLiteralEq), pos: i})
+				i += 2
+
+			} else {
+				ans = append(ans, token{typ: ttOp, value: string(OpEq), pos: i})
+				i++
+			}
+		case r == '!':
+			if i+2 < len(runes) && runes[i+1] == '=' && runes[i+2] == '=' {
+				ans = append(ans, token{typ: ttOp, value: string(OpNotLiteralEq), pos: i})
+				i += 3
+
+			} else if i+1 < len(runes) && runes[i+1] == '=' {
+				ans = append(ans, token{typ: ttOp, value: string(OpNotEq), pos: i})
+				i += 2
+
+			} else {
+				ans = append(ans, token{typ: ttNot, value: "!", pos: i})
+				i++
+			}
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				return nil, &SyntaxError{Query: query, Pos: start, Msg: "unterminated string"}
+			}
+			ans = append(ans, token{typ: ttString, value: string(runes[start+1 : i]), pos: start})
+			i++
+		case isIdentRune(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			ans = append(ans, token{typ: ttIdent, value: string(runes[start:i]), pos: start})
+		default:
+			return nil, &SyntaxError{
+				Query: query, Pos: i, Msg: fmt.Sprintf("unexpected character `%c`", r)}
+		}
+	}
+	ans = append(ans, token{typ: ttEOF, pos: utf8.RuneCountInString(query)})
+	return ans, nil
+}
+
+// ------
+
+const (
+	kwWithin     = "within"
+	kwContaining = "containing"
+)
+
+type parser struct {
+	query  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	ans := p.tokens[p.pos]
+	if ans.typ != ttEOF {
+		p.pos++
+	}
+	return ans
+}
+
+func (p *parser) errorf(tok token, msg string, args ...any) error {
+	return &SyntaxError{Query: p.query, Pos: tok.pos, Msg: fmt.Sprintf(msg, args...)}
+}
+
+func (p *parser) expect(typ tokenType, context string) (token, error) {
+	tok := p.next()
+	if tok.typ != typ {
+		return tok, p.errorf(tok, "expected %s %s, found %s", typ, context, tok.describe())
+	}
+	return tok, nil
+}
+
+func isClauseKeyword(tok token) bool {
+	return tok.typ == ttIdent && (tok.value == kwWithin || tok.value == kwContaining)
+}
+
+// atClause tests whether a within/containing clause starts
+// at the current position
+func (p *parser) atClause() bool {
+	tok := p.peek()
+	return isClauseKeyword(tok) || tok.typ == ttNot && isClauseKeyword(p.peekAt(1))
+}
+
+func (p *parser) atSeqItem() bool {
+	tok := p.peek()
+	switch tok.typ {
+	case ttLBracket, ttString, ttLParen, ttLAngle:
+		return true
+	case ttIdent:
+		return p.peekAt(1).typ == ttColon
+	}
+	return false
+}
+
+func (p *parser) parseAlternatives() (*Alternatives, error) {
+	ans := &Alternatives{Pos: p.peek().pos}
+	for {
+		seq, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		ans.Items = append(ans.Items, seq)
+		if p.peek().typ != ttPipe {
+			break
+		}
+		p.next()
+	}
+	return ans, nil
+}
+
+func (p *parser) parseSequence() (*Sequence, error) {
+	ans := &Sequence{Pos: p.peek().pos}
+	for p.atSeqItem() {
+		item, err := p.parseSeqItem()
+		if err != nil {
+			return nil, err
+		}
+		ans.Items = append(ans.Items, item)
+	}
+	if len(ans.Items) == 0 {
+		tok := p.peek()
+		switch tok.typ {
+		case ttEOF:
+			return nil, p.errorf(tok, "unexpected end of query, expected a token query")
+		case ttIdent:
+			if isClauseKeyword(tok) {
+				return nil, p.errorf(tok, "expected a token query before `%s`", tok.value)
+			}
+			return nil, p.errorf(
+				tok, "unexpected identifier `%s` (values must be enclosed in quotes, e.g. [word=\"%s\"])",
+				tok.value, tok.value)
+		}
+		return nil, p.errorf(tok, "expected a token query (`[...]` or a quoted string), found %s", tok.describe())
+	}
+	return ans, nil
+}
+
+func (p *parser) parseSeqItem() (SeqItem, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case ttLAngle:
+		return p.parseStructure()
+	case ttLParen:
+		p.next()
+		query, err := p.parseAlternatives()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(ttRParen, "to close the group"); err != nil {
+			return nil, err
+		}
+		ans := &Group{Pos: tok.pos, Query: query}
+		ans.Repeat, err = p.parseRepetition()
+		if err != nil {
+			return nil, err
+		}
+		return ans, nil
+	}
+	return p.parseTokenQuery()
+}
+
+func (p *parser) parseTokenQuery() (*TokenQuery, error) {
+	ans := &TokenQuery{Pos: p.peek().pos}
+	if p.peek().typ == ttIdent {
+		label := p.next()
+		p.next() // colon (see atSeqItem)
+		ans.Label = label.value
+		if tok := p.peek(); tok.typ != ttLBracket && tok.typ != ttString {
+			return nil, p.errorf(tok, "expected a token query after label `%s:`, found %s", label.value, tok.describe())
+		}
+	}
+	tok := p.next()
+	if tok.typ == ttString {
+		ans.Expr = &AttrTest{Pos: tok.pos, Op: OpEq, ValuePos: tok.pos, Value: tok.value}
+
+	} else {
+		if p.peek().typ != ttRBracket {
+			expr, err := p.parseAttrOr()
+			if err != nil {
+				return nil, err
+			}
+			ans.Expr = expr
+		}
+		if _, err := p.expect(ttRBracket, "to close the token query"); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	ans.Repeat, err = p.parseRepetition()
+	if err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (p *parser) parseRepetitionNum(context string) (int, error) {
+	tok, err := p.expect(ttIdent, context)
+	if err != nil {
+		return 0, err
+	}
+	ans, err := strconv.Atoi(tok.value)
+	if err != nil || ans < 0 {
+		return 0, p.errorf(tok, "repetition count must be a non-negative integer, found `%s`", tok.value)
+	}
+	return ans, nil
+}
+
+func (p *parser) parseRepetition() (*Repetition, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case ttRepeat:
+		p.next()
+		switch tok.value {
+		case "*":
+			return &Repetition{Min: 0, Max: -1}, nil
+		case "+":
+			return &Repetition{Min: 1, Max: -1}, nil
+		}
+		return &Repetition{Min: 0, Max: 1}, nil
+	case ttLBrace:
+		p.next()
+		ans := &Repetition{}
+		var err error
+		if p.peek().typ != ttComma {
+			ans.Min, err = p.parseRepetitionNum("(minimum count)")
+			if err != nil {
+				return nil, err
+			}
+		}
+		if p.peek().typ == ttComma {
+			p.next()
+			ans.Max = -1
+			if p.peek().typ != ttRBrace {
+				ans.Max, err = p.parseRepetitionNum("(maximum count)")
+				if err != nil {
+					return nil, err
+				}
+			}
+
+		} else {
+			ans.Max = ans.Min
+		}
+		end, err := p.expect(ttRBrace, "to close the repetition")
+		if err != nil {
+			return nil, err
+		}
+		if ans.Max != -1 && ans.Max < ans.Min {
+			return nil, p.errorf(end, "invalid repetition {%d,%d}: maximum is lower than minimum", ans.Min, ans.Max)
+		}
+		return ans, nil
+	}
+	return nil, nil
+}
+
+func (p *parser) parseStructure() (*Structure, error) {
+	p.next() // <
+	ans := &Structure{Kind: StructStart}
+	if p.peek().typ == ttSlash {
+		p.next()
+		ans.Kind = StructEnd
+	}
+	name, err := p.expect(ttIdent, "(structure name)")
+	if err != nil {
+		return nil, err
+	}
+	ans.Pos = name.pos
+	ans.Name = name.value
+	if ans.Kind != StructEnd && p.peek().typ != ttSlash && p.peek().typ != ttRAngle {
+		ans.Expr, err = p.parseAttrOr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if ans.Kind != StructEnd && p.peek().typ == ttSlash {
+		p.next()
+		ans.Kind = StructWhole
+	}
+	if _, err := p.expect(ttRAngle, fmt.Sprintf("to close the structure `%s`", ans.Name)); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (p *parser) parseAttrOr() (AttrExpr, error) {
+	start := p.peek().pos
+	first, err := p.parseAttrAnd()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().typ != ttPipe {
+		return first, nil
+	}
+	ans := &AttrOr{Pos: start, Items: []AttrExpr{first}}
+	for p.peek().typ == ttPipe {
+		p.next()
+		item, err := p.parseAttrAnd()
+		if err != nil {
+			return nil, err
+		}
+		ans.Items = append(ans.Items, item)
+	}
+	return ans, nil
+}
+
+func (p *parser) parseAttrAnd() (AttrExpr, error) {
+	start := p.peek().pos
+	first, err := p.parseAttrNot()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().typ != ttAmp {
+		return first, nil
+	}
+	ans := &AttrAnd{Pos: start, Items: []AttrExpr{first}}
+	for p.peek().typ == ttAmp {
+		p.next()
+		item, err := p.parseAttrNot()
+		if err != nil {
+			return nil, err
+		}
+		ans.Items = append(ans.Items, item)
+	}
+	return ans, nil
+}
+
+func (p *parser) parseAttrNot() (AttrExpr, error) {
+	tok := p.peek()
+	switch tok.typ {
+	case ttNot:
+		p.next()
+		arg, err := p.parseAttrNot()
+		if err != nil {
+			return nil, err
+		}
+		return &AttrNot{Pos: tok.pos, Arg: arg}, nil
+	case ttLParen:
+		p.next()
+		ans, err := p.parseAttrOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(ttRParen, "to close the group"); err != nil {
+			return nil, err
+		}
+		return ans, nil
+	case ttIdent:
+		return p.parseAttrTest()
+	case ttEOF:
+		return nil, p.errorf(tok, "unexpected end of query, expected an attribute test")
+	}
+	return nil, p.errorf(tok, "expected an attribute test (e.g. lemma=\"...\"), found %s", tok.describe())
+}
+
+func (p *parser) parseAttrTest() (*AttrTest, error) {
+	attr := p.next()
+	opTok := p.next()
+	if opTok.typ != ttOp {
+		return nil, p.errorf(
+			opTok, "expected one of `=`, `!=`, `==`, `!==` after `%s`, found %s",
+			attr.value, opTok.describe())
+	}
+	valTok := p.next()
+	if valTok.typ != ttString {
+		msg := "expected a quoted value after `%s`, found %s"
+		if valTok.typ == ttIdent {
+			msg += " (values must be enclosed in quotes)"
+		}
+		return nil, p.errorf(valTok, msg, opTok.value, valTok.describe())
+	}
+	return &AttrTest{
+		Pos:      attr.pos,
+		Attr:     attr.value,
+		Op:       Operator(opTok.value),
+		ValuePos: valTok.pos,
+		Value:    valTok.value,
+	}, nil
+}
+
+func (p *parser) parseLabelAttr() (LabelAttr, error) {
+	label, err := p.expect(ttIdent, "(position label)")
+	if err != nil {
+		return LabelAttr{}, err
+	}
+	if _, err := p.expect(ttDot, fmt.Sprintf("after label `%s`", label.value)); err != nil {
+		return LabelAttr{}, err
+	}
+	attr, err := p.expect(ttIdent, "(attribute name)")
+	if err != nil {
+		return LabelAttr{}, err
+	}
+	return LabelAttr{Pos: label.pos, Label: label.value, Attr: attr.value}, nil
+}
+
+func (p *parser) parseGlobalCondition() (*GlobalCondition, error) {
+	start := p.peek().pos
+	left, err := p.parseLabelAttr()
+	if err != nil {
+		return nil, err
+	}
+	opTok := p.next()
+	if opTok.value != string(OpEq) && opTok.value != string(OpNotEq) {
+		return nil, p.errorf(opTok, "expected `=` or `!=` after `%s`, found %s", left, opTok.describe())
+	}
+	right, err := p.parseLabelAttr()
+	if err != nil {
+		return nil, err
+	}
+	return &GlobalCondition{Pos: start, Left: left, Op: Operator(opTok.value), Right: right}, nil
+}
+
+func (p *parser) parseClause() (*WithinClause, error) {
+	ans := &WithinClause{Pos: p.peek().pos}
+	if p.peek().typ == ttNot {
+		p.next()
+		ans.Negated = true
+	}
+	ans.Containing = p.next().value == kwContaining
+	var err error
+	ans.Query, err = p.parseAlternatives()
+	if err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (p *parser) parse() (*Query, error) {
+	body, err := p.parseAlternatives()
+	if err != nil {
+		return nil, err
+	}
+	ans := &Query{Body: body}
+	for p.peek().typ == ttAmp {
+		p.next()
+		gc, err := p.parseGlobalCondition()
+		if err != nil {
+			return nil, err
+		}
+		ans.Global = append(ans.Global, gc)
+	}
+	for p.atClause() {
+		clause, err := p.parseClause()
+		if err != nil {
+			return nil, err
+		}
+		ans.Clauses = append(ans.Clauses, clause)
+	}
+	if tok := p.peek(); tok.typ != ttEOF {
+		switch tok.typ {
+		case ttRParen:
+			return nil, p.errorf(tok, "unmatched `)`")
+		case ttRBracket:
+			return nil, p.errorf(tok, "unmatched `]`")
+		}
+		return nil, p.errorf(tok, "expected a token query, `within` or end of query, found %s", tok.describe())
+	}
+	return ans, nil
+}
+
+// Parse parses a CQL query. In case of a syntax error, the returned
+// error is of type *SyntaxError.
+//
+// Supported syntax:
+//
+//	[attr="regexp"]              - a token query (ops: =, !=, ==, !==)
+//	"regexp"                     - a token query on the default attribute
+//	[]                           - any token
+//	[a="x" & (b="y" | !c="z")]   - boolean expressions
+//	1:[...]                      - a labeled position
+//	item*, item+, item?, item{n,m} - repetition
+//	(... | ...)                  - groups and alternatives
+//	<s>, </s>, <doc id="x"/>     - structures
+//	... & 1.tag = 2.tag          - global conditions
+//	... within <s/>, ... !containing "x" - within/containing clauses
+func Parse(query string) (*Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{query: query, tokens: tokens}
+	return p.parse()
+}
+
+// MustParse is like Parse but it panics in case of an error.
+// It is intended for static queries.
+func MustParse(query string) *Query {
+	ans, err := Parse(query)
+	if err != nil {
+		panic(err)
+	}
+	return ans
+}
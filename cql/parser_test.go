@@ -0,0 +1,129 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCanonical(t *testing.T) {
+	cases := map[string]string{
+		`[lemma="dům"]`:                        `[lemma="dům"]`,
+		`"dům"`:                                `"dům"`,
+		`'dům'`:                                `"dům"`,
+		`[ ]`:                                  `[]`,
+		`[lemma = "a"&tag!="N.*"]`:             `[lemma="a" & tag!="N.*"]`,
+		`[lemma="a" & (tag="N.*"|tag="A.*")]`:  `[lemma="a" & (tag="N.*" | tag="A.*")]`,
+		`[!(lemma="a" & tag="N.*")]`:           `[!(lemma="a" & tag="N.*")]`,
+		`[word=="a.b" | word!=="c"]`:           `[word=="a.b" | word!=="c"]`,
+		`"a" []{1,3} "b"`:                      `"a" []{1,3} "b"`,
+		`[]{2} []{2,} []{,2} []* []+ []?`:      `[]{2} []{2,} []{0,2} []* []+ []?`,
+		`("a" | "b" "c")+`:                     `("a" | "b" "c")+`,
+		`<s> "a" </s>`:                         `<s> "a" </s>`,
+		`<doc txtype="FIC" & pubyear="2000"/>`: `<doc txtype="FIC" & pubyear="2000"/>`,
+		`1:[tag="A.*"] 2:[tag="N.*"] & 1.case = 2.case`: `1:[tag="A.*"] 2:[tag="N.*"] & 1.case = 2.case`,
+		`"a" within <s/> !within <doc id="x"/>`:         `"a" within <s/> !within <doc id="x"/>`,
+		`<s/> containing "a" "b"`:                       `<s/> containing "a" "b"`,
+		`"a" | "b" within <s/>`:                         `"a" | "b" within <s/>`,
+		`[word="a\"b"]`:                                 `[word="a\"b"]`,
+		`[word='a"b']`:                                  `[word="a\"b"]`,
+		`[word="a\\"]`:                                  `[word="a\\"]`,
+	}
+	for src, expected := range cases {
+		q, err := Parse(src)
+		if assert.NoError(t, err, src) {
+			assert.Equal(t, expected, q.String(), src)
+			q2, err := Parse(q.String())
+			assert.NoError(t, err)
+			assert.Equal(t, expected, q2.String(), "reparsed "+src)
+		}
+	}
+}
+
+func TestParseStructure(t *testing.T) {
+	q := MustParse(`"a" within <doc txtype="FIC"/>`)
+	assert.Len(t, q.Clauses, 1)
+	s := q.Clauses[0].Query.Items[0].Items[0].(*Structure)
+	assert.Equal(t, "doc", s.Name)
+	assert.Equal(t, StructWhole, s.Kind)
+	assert.Equal(t, 12, s.Pos)
+	test := s.Expr.(*AttrTest)
+	assert.Equal(t, "txtype", test.Attr)
+	assert.Equal(t, 16, test.Pos)
+	assert.Equal(t, 23, test.ValuePos)
+}
+
+func TestParsePositions(t *testing.T) {
+	q := MustParse(`1:[lemma="ž" & tag="N.*"]`)
+	tq := q.Body.Items[0].Items[0].(*TokenQuery)
+	assert.Equal(t, "1", tq.Label)
+	and := tq.Expr.(*AttrAnd)
+	assert.Equal(t, 3, and.Items[0].(*AttrTest).Pos)
+	assert.Equal(t, 15, and.Items[1].(*AttrTest).Pos)
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]int{
+		`[lemma="a"`:    10,
+		`[lemma=a]`:     7,
+		`[lemma "a"]`:   7,
+		`"a`:            0,
+		`"a" )`:         4,
+		`within <s/>`:   0,
+		`[]{3,1}`:       6,
+		`<s`:            2,
+		`"a" foo`:       4,
+		`1:"a" & 1.tag`: 13,
+		`[lemma="a"] #`: 12,
+		``:              0,
+		`1: within`:     3,
+	}
+	for src, pos := range cases {
+		_, err := Parse(src)
+		if assert.Error(t, err, src) {
+			serr, ok := err.(*SyntaxError)
+			assert.True(t, ok)
+			assert.Equal(t, pos, serr.Pos, src+": "+serr.Msg)
+		}
+	}
+}
+
+func TestSyntaxErrorContext(t *testing.T) {
+	_, err := Parse(`[lemma=a]`)
+	assert.Equal(t, "[lemma=a]\n       ^", err.(*SyntaxError).Context())
+	assert.Contains(t, err.Error(), "values must be enclosed in quotes")
+}
+
+func TestWalk(t *testing.T) {
+	q := MustParse(`[lemma="a" & !tag="N.*"] ("b" | <s/>) within <doc id="x"/>`)
+	var attrs []string
+	Walk(q, func(n Node) bool {
+		if v, ok := n.(*AttrTest); ok {
+			attrs = append(attrs, v.Attr)
+		}
+		return true
+	})
+	assert.Equal(t, []string{"lemma", "tag", "", "id"}, attrs)
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"a\"b"`, Quote(`a"b`))
+	assert.Equal(t, `"a\"b"`, Quote(`a\"b`))
+	assert.Equal(t, `"a\.b\\"`, Quote(`a\.b\`))
+}
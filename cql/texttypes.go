@@ -0,0 +1,405 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// regexpMetaChars are characters escaped by regexp.QuoteMeta
+const regexpMetaChars = `\.+*?()|[]{}^$`
+
+func splitStructAttr(attr string) (string, string, error) {
+	st, name, ok := strings.Cut(attr, ".")
+	if !ok || st == "" || name == "" {
+		return "", "", fmt.Errorf("invalid structural attribute `%s`", attr)
+	}
+	return st, name, nil
+}
+
+func selectionTest(attr string, sel corp.TTSelection) (*AttrTest, error) {
+	ans := &AttrTest{Attr: attr, Op: OpEq}
+	if sel.Negated {
+		ans.Op = OpNotEq
+	}
+	switch {
+	case sel.Range != nil:
+		rx, err := numRangeRegexp(*sel.Range)
+		if err != nil {
+			return nil, err
+		}
+		ans.Value = rx
+	case sel.Regexp != "":
+		ans.Value = sel.UnanchoredRegexp()
+	default:
+		sorted := make([]string, len(sel.Values))
+		for i, v := range sel.Values {
+			sorted[i] = regexp.QuoteMeta(v)
+		}
+		sort.Strings(sorted)
+		ans.Value = strings.Join(slices.Compact(sorted), "|")
+	}
+	return ans, nil
+}
+
+// SelectionsClauses converts text type selections into `within` clauses,
+// one for each structure (e.g. `within <doc txtype="A|B" & pubyear="2001"/>`).
+// Plain values are escaped so they are matched literally, numeric ranges
+// are converted to regular expressions and negated selections use the `!=`
+// operator. Regular expressions are used without redundant anchors (in CQL,
+// they match whole values, see corp.TTSelection.Regexp). Structures, attributes and values
+// are sorted to produce a stable output. Plain selections with no values
+// are ignored. Please note that the selections are not validated here
+// (see corp.TTSelections.Validate). See SelectionsFromClauses for
+// the inverse conversion.
+func SelectionsClauses(sels corp.TTSelections) ([]*WithinClause, error) {
+	byStruct := make(map[string][]*AttrTest)
+	for _, attr := range sels.Attrs() {
+		sel := sels[attr]
+		if sel.IsPlain() && len(sel.Values) == 0 {
+			continue
+		}
+		st, name, err := splitStructAttr(attr)
+		if err != nil {
+			return nil, err
+		}
+		test, err := selectionTest(name, sel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection of %s: %w", attr, err)
+		}
+		byStruct[st] = append(byStruct[st], test)
+	}
+	structs := make([]string, 0, len(byStruct))
+	for st := range byStruct {
+		structs = append(structs, st)
+	}
+	sort.Strings(structs)
+	ans := make([]*WithinClause, len(structs))
+	for i, st := range structs {
+		tests := byStruct[st]
+		s := &Structure{Name: st, Kind: StructWhole}
+		if len(tests) == 1 {
+			s.Expr = tests[0]
+
+		} else {
+			and := &AttrAnd{Items: make([]AttrExpr, len(tests))}
+			for j, t := range tests {
+				and.Items[j] = t
+			}
+			s.Expr = and
+		}
+		ans[i] = &WithinClause{
+			Query: &Alternatives{Items: []*Sequence{{Items: []SeqItem{s}}}},
+		}
+	}
+	return ans, nil
+}
+
+// SelectionsToCQL renders text type selections as CQL `within` clauses
+// (see SelectionsClauses). Empty selections produce an empty string.
+func SelectionsToCQL(sels corp.TTSelections) (string, error) {
+	clauses, err := SelectionsClauses(sels)
+	if err != nil {
+		return "", err
+	}
+	items := make([]string, len(clauses))
+	for i, c := range clauses {
+		items[i] = c.String()
+	}
+	return strings.Join(items, " "), nil
+}
+
+// TextTypesClauses converts text types into `within` clauses.
+// See SelectionsClauses for details.
+func TextTypesClauses(tt corp.TextTypes) ([]*WithinClause, error) {
+	return SelectionsClauses(tt.Selections())
+}
+
+// TextTypesToCQL renders text type selections as CQL `within` clauses
+// (see TextTypesClauses). Empty selections produce an empty string.
+func TextTypesToCQL(tt corp.TextTypes) (string, error) {
+	return SelectionsToCQL(tt.Selections())
+}
+
+// ------
+
+// unescapeLiteral splits a regexp produced by TextTypesClauses into
+// literal values. An error is returned in case the regexp contains
+// unescaped metacharacters (i.e. it is not a list of literal values).
+func unescapeLiteral(rx string) ([]string, error) {
+	ans := make([]string, 0, 3)
+	var curr strings.Builder
+	runes := []rune(rx)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			curr.WriteRune(runes[i])
+		case r == '|':
+			ans = append(ans, curr.String())
+			curr.Reset()
+		case strings.ContainsRune(regexpMetaChars, r):
+			return nil, fmt.Errorf("value `%s` is not a list of literal values", rx)
+		default:
+			curr.WriteRune(r)
+		}
+	}
+	ans = append(ans, curr.String())
+	return ans, nil
+}
+
+func literalValues(t *AttrTest) ([]string, error) {
+	switch t.Op {
+	case OpEq:
+		return unescapeLiteral(t.Value)
+	case OpLiteralEq:
+		return []string{t.Value}, nil
+	}
+	return nil, fmt.Errorf("operator `%s` cannot be expressed as text types", t.Op)
+}
+
+func addTextTypesTest(st string, expr AttrExpr, ans corp.TextTypes) error {
+	var attr string
+	var values []string
+	switch e := expr.(type) {
+	case *AttrTest:
+		var err error
+		attr = e.Attr
+		values, err = literalValues(e)
+		if err != nil {
+			return err
+		}
+	case *AttrOr:
+		// alternatives are supported only for a single attribute
+		// (e.g. txtype="A" | txtype="B")
+		for _, item := range e.Items {
+			t, ok := item.(*AttrTest)
+			if !ok || (attr != "" && t.Attr != attr) {
+				return fmt.Errorf("alternatives of different attributes cannot be expressed as text types")
+			}
+			attr = t.Attr
+			tmp, err := literalValues(t)
+			if err != nil {
+				return err
+			}
+			values = append(values, tmp...)
+		}
+	case *AttrAnd:
+		for _, item := range e.Items {
+			if err := addTextTypesTest(st, item, ans); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("expression `%s` cannot be expressed as text types", expr)
+	}
+	key := st + "." + attr
+	if _, ok := ans[key]; ok {
+		return fmt.Errorf("attribute `%s` is constrained more than once", key)
+	}
+	ans[key] = values
+	return nil
+}
+
+// clauseStructure returns the structure of a clause in case the clause
+// has the form produced by SelectionsClauses (a positive `within`
+// clause with a single whole structure).
+func clauseStructure(c *WithinClause) (*Structure, error) {
+	if c.Negated || c.Containing {
+		return nil, fmt.Errorf("clause `%s` cannot be expressed as text types", c)
+	}
+	if len(c.Query.Items) != 1 || len(c.Query.Items[0].Items) != 1 {
+		return nil, fmt.Errorf("clause `%s` cannot be expressed as text types", c)
+	}
+	s, ok := c.Query.Items[0].Items[0].(*Structure)
+	if !ok || s.Kind != StructWhole {
+		return nil, fmt.Errorf("clause `%s` cannot be expressed as text types", c)
+	}
+	return s, nil
+}
+
+// parseClauses parses either a complete query or just `within` clauses
+func parseClauses(query string) ([]*WithinClause, error) {
+	src := query
+	trimmed := strings.TrimSpace(query)
+	if trimmed == "" {
+		return []*WithinClause{}, nil
+	}
+	if strings.HasPrefix(trimmed, kwWithin) {
+		src = "[] " + query
+	}
+	q, err := Parse(src)
+	if err != nil {
+		if serr, ok := err.(*SyntaxError); ok && src != query {
+			serr.Query = query
+			serr.Pos -= 3
+		}
+		return nil, err
+	}
+	return q.Clauses, nil
+}
+
+// TextTypesFromClauses converts `within` clauses back to text type
+// selections. Only positive `within` clauses with a single whole
+// structure constrained by a conjunction of attributes matching literal
+// values (as produced by TextTypesClauses) are supported.
+func TextTypesFromClauses(clauses []*WithinClause) (corp.TextTypes, error) {
+	ans := make(corp.TextTypes)
+	for _, c := range clauses {
+		s, err := clauseStructure(c)
+		if err != nil {
+			return nil, err
+		}
+		if s.Expr == nil {
+			continue
+		}
+		tmp := make(corp.TextTypes)
+		if err := addTextTypesTest(s.Name, s.Expr, tmp); err != nil {
+			return nil, fmt.Errorf("clause `%s` cannot be expressed as text types: %w", c, err)
+		}
+		for k, v := range tmp {
+			if _, ok := ans[k]; ok {
+				return nil, fmt.Errorf("attribute `%s` is constrained more than once", k)
+			}
+			ans[k] = v
+		}
+	}
+	return ans, nil
+}
+
+// TextTypesFromCQL parses `within` clauses (e.g. as produced
+// by TextTypesToCQL) into text type selections. The input can be
+// either a complete query or just the clauses (starting with `within`).
+// See TextTypesFromClauses for supported expressions.
+func TextTypesFromCQL(query string) (corp.TextTypes, error) {
+	clauses, err := parseClauses(query)
+	if err != nil {
+		return nil, err
+	}
+	return TextTypesFromClauses(clauses)
+}
+
+// ------
+
+// selectionOf converts a single attribute test into a selection.
+// Values which are lists of escaped literals are converted
+// to plain values, other values to regular expressions.
+func selectionOf(t *AttrTest) corp.TTSelection {
+	var ans corp.TTSelection
+	switch t.Op {
+	case OpEq, OpNotEq:
+		if values, err := unescapeLiteral(t.Value); err == nil {
+			ans.Values = values
+
+		} else {
+			ans.Regexp = t.Value
+		}
+	default:
+		ans.Values = []string{t.Value}
+	}
+	ans.Negated = t.Op.IsNegative()
+	return ans
+}
+
+func addSelectionTest(st string, expr AttrExpr, ans corp.TTSelections) error {
+	var attr string
+	var sel corp.TTSelection
+	switch e := expr.(type) {
+	case *AttrTest:
+		attr = e.Attr
+		sel = selectionOf(e)
+	case *AttrOr:
+		// alternatives are supported only for positive tests of plain
+		// values of a single attribute (e.g. txtype="A" | txtype="B")
+		for _, item := range e.Items {
+			t, ok := item.(*AttrTest)
+			if !ok || (attr != "" && t.Attr != attr) {
+				return fmt.Errorf("alternatives of different attributes cannot be expressed as text types")
+			}
+			attr = t.Attr
+			tmp := selectionOf(t)
+			if !tmp.IsPlain() {
+				return fmt.Errorf("alternatives of `%s` cannot be expressed as text types", t)
+			}
+			sel.Values = append(sel.Values, tmp.Values...)
+		}
+	case *AttrAnd:
+		for _, item := range e.Items {
+			if err := addSelectionTest(st, item, ans); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("expression `%s` cannot be expressed as text types", expr)
+	}
+	key := st + "." + attr
+	if _, ok := ans[key]; ok {
+		return fmt.Errorf("attribute `%s` is constrained more than once", key)
+	}
+	ans[key] = sel
+	return nil
+}
+
+// SelectionsFromClauses converts `within` clauses back to text type
+// selections. Compared with TextTypesFromClauses, negated tests (`!=`,
+// `!==`) and regular expressions are supported too. Please note
+// that numeric ranges produced by SelectionsClauses are read back
+// as equivalent regular expressions (i.e. Range is never set).
+func SelectionsFromClauses(clauses []*WithinClause) (corp.TTSelections, error) {
+	ans := make(corp.TTSelections)
+	for _, c := range clauses {
+		s, err := clauseStructure(c)
+		if err != nil {
+			return nil, err
+		}
+		if s.Expr == nil {
+			continue
+		}
+		tmp := make(corp.TTSelections)
+		if err := addSelectionTest(s.Name, s.Expr, tmp); err != nil {
+			return nil, fmt.Errorf("clause `%s` cannot be expressed as text types: %w", c, err)
+		}
+		for k, v := range tmp {
+			if _, ok := ans[k]; ok {
+				return nil, fmt.Errorf("attribute `%s` is constrained more than once", k)
+			}
+			ans[k] = v
+		}
+	}
+	return ans, nil
+}
+
+// SelectionsFromCQL parses `within` clauses (e.g. as produced
+// by SelectionsToCQL) into text type selections. The input can be
+// either a complete query or just the clauses (starting with `within`).
+// See SelectionsFromClauses for supported expressions.
+func SelectionsFromCQL(query string) (corp.TTSelections, error) {
+	clauses, err := parseClauses(query)
+	if err != nil {
+		return nil, err
+	}
+	return SelectionsFromClauses(clauses)
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cql
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextTypesToCQL(t *testing.T) {
+	tt := corp.TextTypes{
+		"doc.txtype":  {"NMG: magazines", "FIC: novels"},
+		"doc.pubyear": {"2001"},
+		"text.author": {`Čapek, K. (1890-1938)`, `"Quoted"`},
+		"opus.id":     {},
+	}
+	ans, err := TextTypesToCQL(tt)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`within <doc pubyear="2001" & txtype="FIC: novels|NMG: magazines"/> `+
+			`within <text author="\"Quoted\"|Čapek, K\. \(1890-1938\)"/>`,
+		ans,
+	)
+	back, err := TextTypesFromCQL(ans)
+	assert.NoError(t, err)
+	delete(tt, "opus.id")
+	assert.Equal(t, corp.TextTypes{
+		"doc.txtype":  {"FIC: novels", "NMG: magazines"},
+		"doc.pubyear": {"2001"},
+		"text.author": {`"Quoted"`, `Čapek, K. (1890-1938)`},
+	}, back)
+
+	ans, err = TextTypesToCQL(corp.TextTypes{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", ans)
+
+	_, err = TextTypesToCQL(corp.TextTypes{"txtype": {"A"}})
+	assert.Error(t, err)
+}
+
+func TestTextTypesFromCQL(t *testing.T) {
+	ans, err := TextTypesFromCQL(`[lemma="a"] within <doc txtype="A" | txtype=="B.C"/> within <s/>`)
+	assert.NoError(t, err)
+	assert.Equal(t, corp.TextTypes{"doc.txtype": {"A", "B.C"}}, ans)
+
+	ans, err = TextTypesFromCQL("")
+	assert.NoError(t, err)
+	assert.Equal(t, corp.TextTypes{}, ans)
+
+	for _, q := range []string{
+		`within <doc txtype="A.*"/>`,
+		`within <doc txtype!="A"/>`,
+		`!within <doc txtype="A"/>`,
+		`within <doc txtype="A" | id="B"/>`,
+		`within <doc txtype="A" & txtype="B"/>`,
+		`within <doc txtype="A"/> within <doc txtype="B"/>`,
+		`within <doc txtype="A"/> "b"`,
+		`within <doc>`,
+	} {
+		_, err := TextTypesFromCQL(q)
+		assert.Error(t, err, q)
+	}
+
+	_, err = TextTypesFromCQL(`within <doc txtype=A/>`)
+	serr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 19, serr.Pos)
+}
+
+func TestSelectionsToCQL(t *testing.T) {
+	from, to := 1990, 2005
+	ans, err := SelectionsToCQL(corp.TTSelections{
+		"doc.pubyear": {Range: &corp.NumRange{From: &from, To: &to}},
+		"doc.txtype":  {Values: []string{"B", "A", "B"}, Negated: true},
+		"text.author": {Regexp: `Čapek.*`},
+		"text.id":     {},
+	})
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`within <doc pubyear="199[0-9]|200[0-5]" & txtype!="A|B"/> within <text author="Čapek.*"/>`,
+		ans,
+	)
+	_, err = SelectionsToCQL(corp.TTSelections{"doc.pubyear": {Range: &corp.NumRange{}}})
+	assert.Error(t, err)
+
+	ans, err = SelectionsToCQL(corp.TTSelections{"text.author": {Regexp: `^Čapek.*$`}})
+	assert.NoError(t, err)
+	assert.Equal(t, `within <text author="Čapek.*"/>`, ans)
+}
+
+func TestNumRangeRegexp(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	cases := []corp.NumRange{
+		{From: intPtr(0), To: intPtr(0)},
+		{From: intPtr(7), To: intPtr(123)},
+		{From: intPtr(99), To: intPtr(101)},
+		{From: intPtr(1990), To: intPtr(2005)},
+		{To: intPtr(1000)},
+		{From: intPtr(95)},
+	}
+	for _, c := range cases {
+		rx, err := numRangeRegexp(c)
+		assert.NoError(t, err)
+		r := regexp.MustCompile("^(" + rx + ")$")
+		for i := 0; i < 3000; i++ {
+			expected := (c.From == nil || i >= *c.From) && (c.To == nil || i <= *c.To)
+			assert.Equal(t, expected, r.MatchString(strconv.Itoa(i)), "%s: %d", rx, i)
+		}
+	}
+}
+
+func TestSelectionsFromCQL(t *testing.T) {
+	from := 1990
+	sels := corp.TTSelections{
+		"doc.txtype":  {Values: []string{"A", "B.1"}, Negated: true},
+		"doc.pubyear": {Range: &corp.NumRange{From: &from}},
+		"text.author": {Regexp: `Čapek.*`},
+		"text.id":     {Values: []string{"x"}},
+	}
+	query, err := SelectionsToCQL(sels)
+	assert.NoError(t, err)
+	back, err := SelectionsFromCQL(query)
+	assert.NoError(t, err)
+	rx, err := numRangeRegexp(*sels["doc.pubyear"].Range)
+	assert.NoError(t, err)
+	assert.Equal(t, corp.TTSelections{
+		"doc.txtype":  {Values: []string{"A", "B.1"}, Negated: true},
+		"doc.pubyear": {Regexp: rx},
+		"text.author": {Regexp: `Čapek.*`},
+		"text.id":     {Values: []string{"x"}},
+	}, back)
+	for _, v := range []string{"1989", "1990", "2024", "12345"} {
+		assert.Equal(t, sels["doc.pubyear"].Matches(v), back["doc.pubyear"].Matches(v), v)
+	}
+
+	back, err = SelectionsFromCQL(`[] within <doc txtype!=="A" & id="1" | id=="2"/>`)
+	assert.Error(t, err)
+	back, err = SelectionsFromCQL(`within <doc txtype!=="A" & (id="1" | id=="2")/>`)
+	assert.NoError(t, err)
+	assert.Equal(t, corp.TTSelections{
+		"doc.txtype": {Values: []string{"A"}, Negated: true},
+		"doc.id":     {Values: []string{"1", "2"}},
+	}, back)
+
+	_, err = SelectionsFromCQL(`within <doc id!="1" | id!="2"/>`)
+	assert.Error(t, err)
+	_, err = SelectionsFromCQL(`within <doc id="1"/> within <doc id="2"/>`)
+	assert.Error(t, err)
+	_, err = SelectionsFromCQL(`within !<doc id="1"/>`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// DiagnosticCode identifies a kind of problem found in a query
+type DiagnosticCode string
+
+const (
+	DiagUnknownPosAttr    DiagnosticCode = "unknownPosAttr"
+	DiagUnknownStructure  DiagnosticCode = "unknownStructure"
+	DiagUnknownStructAttr DiagnosticCode = "unknownStructAttr"
+	DiagUndefinedLabel    DiagnosticCode = "undefinedLabel"
+	DiagDuplicateLabel    DiagnosticCode = "duplicateLabel"
+)
+
+// diagnosticMessages contains localized message templates. The only
+// argument is the diagnostic subject.
+var diagnosticMessages = map[DiagnosticCode]map[string]string{
+	DiagUnknownPosAttr: {
+		"en": "unknown positional attribute `%s`",
+		"cs": "neznámý poziční atribut `%s`",
+	},
+	DiagUnknownStructure: {
+		"en": "unknown structure `%s`",
+		"cs": "neznámá struktura `%s`",
+	},
+	DiagUnknownStructAttr: {
+		"en": "unknown structural attribute `%s`",
+		"cs": "neznámý strukturní atribut `%s`",
+	},
+	DiagUndefinedLabel: {
+		"en": "undefined position label `%s`",
+		"cs": "nedefinované návěští pozice `%s`",
+	},
+	DiagDuplicateLabel: {
+		"en": "position label `%s` is defined more than once",
+		"cs": "návěští pozice `%s` je definováno vícekrát",
+	},
+}
+
+// Diagnostic describes a semantic problem found in a query
+type Diagnostic struct {
+	Code DiagnosticCode `json:"code"`
+
+	// Pos is a zero-based position (in runes) of the problem
+	// within the query
+	Pos int `json:"pos"`
+
+	// Len is the length (in runes) of the problematic part
+	Len int `json:"len"`
+
+	// Subject is the problematic name (e.g. an attribute). Structural
+	// attributes are reported including the structure (e.g. "doc.id").
+	Subject string `json:"subject"`
+
+	// Message is a localized description of the problem
+	Message string `json:"message"`
+}
+
+// LocaleMessage returns a localized description of the problem.
+// In case the `lang` is not present, "en" version is returned.
+func (d Diagnostic) LocaleMessage(lang string) string {
+	tpl := diagnosticMessages[d.Code][lang]
+	if tpl == "" {
+		tpl = diagnosticMessages[d.Code]["en"]
+	}
+	return fmt.Sprintf(tpl, d.Subject)
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d: %s", d.Pos, d.LocaleMessage("en"))
+}
+
+// ------
+
+type validator struct {
+	setup       *corp.CorpusSetup
+	lang        string
+	structures  map[string]bool
+	structAttrs map[string]bool
+	labels      map[string]int
+	ans         []Diagnostic
+}
+
+func (v *validator) add(code DiagnosticCode, pos, length int, subject string) {
+	d := Diagnostic{
+		Code:    code,
+		Pos:     pos,
+		Len:     length,
+		Subject: subject,
+	}
+	d.Message = d.LocaleMessage(v.lang)
+	v.ans = append(v.ans, d)
+}
+
+func (v *validator) checkPosAttr(attr string, pos int) {
+	if attr != "" && len(v.setup.PosAttrs) > 0 && !v.setup.PosAttrs.Contains(attr) {
+		v.add(DiagUnknownPosAttr, pos, utf8.RuneCountInString(attr), attr)
+	}
+}
+
+func (v *validator) checkStructure(s *Structure) {
+	if !v.structures[s.Name] {
+		v.add(DiagUnknownStructure, s.Pos, utf8.RuneCountInString(s.Name), s.Name)
+		return
+	}
+	if s.Expr == nil {
+		return
+	}
+	Walk(s.Expr, func(n Node) bool {
+		if t, ok := n.(*AttrTest); ok {
+			if !v.structAttrs[s.Name+"."+t.Attr] {
+				v.add(DiagUnknownStructAttr, t.Pos, utf8.RuneCountInString(t.Attr), s.Name+"."+t.Attr)
+			}
+		}
+		return true
+	})
+}
+
+func (v *validator) visit(n Node) bool {
+	switch tn := n.(type) {
+	case *TokenQuery:
+		if tn.Label != "" {
+			if _, ok := v.labels[tn.Label]; ok {
+				v.add(DiagDuplicateLabel, tn.Pos, utf8.RuneCountInString(tn.Label), tn.Label)
+
+			} else {
+				v.labels[tn.Label] = tn.Pos
+			}
+		}
+		if tn.Expr != nil {
+			Walk(tn.Expr, func(n Node) bool {
+				if t, ok := n.(*AttrTest); ok {
+					v.checkPosAttr(t.Attr, t.Pos)
+				}
+				return true
+			})
+		}
+		return false
+	case *Structure:
+		v.checkStructure(tn)
+		return false
+	}
+	return true
+}
+
+func (v *validator) checkGlobal(q *Query) {
+	for _, gc := range q.Global {
+		for _, la := range []LabelAttr{gc.Left, gc.Right} {
+			if _, ok := v.labels[la.Label]; !ok {
+				v.add(DiagUndefinedLabel, la.Pos, utf8.RuneCountInString(la.Label), la.Label)
+			}
+			v.checkPosAttr(la.Attr, la.Pos+utf8.RuneCountInString(la.Label)+1)
+		}
+	}
+}
+
+// Validate checks the query against the corpus configuration:
+//
+//   - positional attributes must be listed in setup.PosAttrs
+//     (the check is skipped if the corpus has no attributes configured)
+//   - structures must be known to the corpus (see corp.CorpusSetup.KnownStructures)
+//     or used by its text properties (setup.TextProperties)
+//   - structural attributes must be listed in setup.ConcTextPropsAttrs
+//     or setup.TextProperties
+//   - labels used in global conditions must be defined
+//
+// Diagnostics are sorted by their position and their messages
+// are localized using `lang` (with "en" as a fallback).
+func Validate(q *Query, setup *corp.CorpusSetup, lang string) []Diagnostic {
+	v := &validator{
+		setup:       setup,
+		lang:        lang,
+		structures:  make(map[string]bool),
+		structAttrs: make(map[string]bool),
+		labels:      make(map[string]int),
+		ans:         make([]Diagnostic, 0, 5),
+	}
+	for _, s := range setup.KnownStructures() {
+		v.structures[s] = true
+	}
+	attrs := append([]string{}, setup.ConcTextPropsAttrs...)
+	for _, conf := range setup.TextProperties {
+		attrs = append(attrs, conf.Name)
+	}
+	for _, attr := range attrs {
+		if st, _, ok := strings.Cut(attr, "."); ok {
+			v.structures[st] = true
+			v.structAttrs[attr] = true
+		}
+	}
+	Walk(q, v.visit)
+	v.checkGlobal(q)
+	sort.SliceStable(v.ans, func(i, j int) bool { return v.ans[i].Pos < v.ans[j].Pos })
+	return v.ans
+}
+
+// ValidateQuery parses and validates the query (see Validate).
+// Syntax errors are returned as *SyntaxError.
+func ValidateQuery(query string, setup *corp.CorpusSetup, lang string) ([]Diagnostic, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return Validate(q, setup, lang), nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cql
+
+import (
+	"testing"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSetup() *corp.CorpusSetup {
+	return &corp.CorpusSetup{
+		ID:                   "syn2020",
+		PosAttrs:             corp.PosAttrList{{Name: "word"}, {Name: "lemma"}, {Name: "tag"}},
+		ConcMarkupStructures: []string{"s", "p"},
+		ConcTextPropsAttrs:   []string{"doc.title"},
+		TextProperties: corp.TextTypeProperties{
+			corp.TextPropertyTextType: {Name: "doc.txtype"},
+			corp.TextPropertyAuthor:   {Name: "text.author"},
+		},
+	}
+}
+
+func TestValidateOK(t *testing.T) {
+	diags, err := ValidateQuery(
+		`1:[lemma="a" & tag="N.*"] 2:"b" & 1.tag = 2.tag within <doc txtype="FIC"/> within <s/> !within <text author="x"/>`,
+		testSetup(), "en",
+	)
+	assert.NoError(t, err)
+	assert.Empty(t, diags)
+}
+
+func TestValidateProblems(t *testing.T) {
+	diags, err := ValidateQuery(
+		`1:[lemma="a" & ptag="N.*"] 1:[] & 1.tag = 3.foo within <doc pubyear="2000"/> within <sp/>`,
+		testSetup(), "cs",
+	)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]Diagnostic{
+			{Code: DiagUnknownPosAttr, Pos: 15, Len: 4, Subject: "ptag",
+				Message: "neznámý poziční atribut `ptag`"},
+			{Code: DiagDuplicateLabel, Pos: 27, Len: 1, Subject: "1",
+				Message: "návěští pozice `1` je definováno vícekrát"},
+			{Code: DiagUndefinedLabel, Pos: 42, Len: 1, Subject: "3",
+				Message: "nedefinované návěští pozice `3`"},
+			{Code: DiagUnknownPosAttr, Pos: 44, Len: 3, Subject: "foo",
+				Message: "neznámý poziční atribut `foo`"},
+			{Code: DiagUnknownStructAttr, Pos: 60, Len: 7, Subject: "doc.pubyear",
+				Message: "neznámý strukturní atribut `doc.pubyear`"},
+			{Code: DiagUnknownStructure, Pos: 85, Len: 2, Subject: "sp",
+				Message: "neznámá struktura `sp`"},
+		},
+		diags,
+	)
+	assert.Equal(t, "unknown structure `sp`", diags[5].LocaleMessage("de"))
+}
+
+func TestValidateSyntaxError(t *testing.T) {
+	_, err := ValidateQuery(`[lemma=`, testSetup(), "en")
+	_, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+}
@@ -4,11 +4,13 @@ go 1.23
 
 toolchain go1.23.4
 
-require github.com/stretchr/testify v1.9.0
+require (
+	github.com/czcorpus/cnc-gokit v0.19.0
+	github.com/stretchr/testify v1.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
-	github.com/czcorpus/cnc-gokit v0.19.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,271 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema generates JSON Schema and OpenAPI component
+// definitions for the shared data types so API consumers
+// (typically frontends generating TypeScript types) do not
+// have to maintain them by hand.
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Format specifies the flavor of the produced schema document.
+type Format string
+
+const (
+	FormatJSONSchema Format = "jsonschema"
+	FormatOpenAPI    Format = "openapi"
+)
+
+// Validate tests whether the value is one of the supported formats.
+func (f Format) Validate() error {
+	if f == FormatJSONSchema || f == FormatOpenAPI {
+		return nil
+	}
+	return fmt.Errorf("invalid schema format: %s", f)
+}
+
+func (f Format) refPrefix() string {
+	if f == FormatOpenAPI {
+		return "#/components/schemas/"
+	}
+	return "#/$defs/"
+}
+
+// ------
+
+// Discriminator is an OpenAPI discriminator object. JSON Schema
+// does not know the keyword, so it is omitted there and the variants
+// are distinguished only by their single-valued `enum` properties.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// Schema is a subset of JSON Schema / OpenAPI schema object
+// sufficient for describing our data types.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Discriminator        *Discriminator     `json:"discriminator,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// ------
+
+// CustomSchemaFn produces a schema for a type the reflection
+// cannot describe properly (typically a type with a custom
+// JSON marshaler).
+type CustomSchemaFn func(g *Generator) *Schema
+
+// Generator collects named schemas of Go types. Struct types
+// (and types with a registered custom schema) are stored as named
+// definitions and referenced via `$ref`, everything else is inlined.
+type Generator struct {
+	format Format
+	defs   map[string]*Schema
+	names  map[reflect.Type]string
+	custom map[reflect.Type]CustomSchemaFn
+}
+
+// Register sets a custom schema for the type of the provided value.
+// To register an interface type, pass a nil pointer to it
+// (e.g. `(*concordance.LineElement)(nil)`).
+func (g *Generator) Register(v any, fn CustomSchemaFn) {
+	g.custom[typeOf(v)] = fn
+}
+
+// Ref returns a reference to a named definition.
+func (g *Generator) Ref(name string) *Schema {
+	return &Schema{Ref: g.format.refPrefix() + name}
+}
+
+// Add generates a schema for the type of the provided value (including all the
+// types it depends on) and returns a reference to it.
+func (g *Generator) Add(v any) *Schema {
+	return g.schemaOf(typeOf(v))
+}
+
+// Definitions returns all the named schemas collected so far.
+func (g *Generator) Definitions() map[string]*Schema {
+	return g.defs
+}
+
+// Document returns a complete document containing all the collected
+// definitions in the format the generator has been created for.
+func (g *Generator) Document(title, version string) map[string]any {
+	if g.format == FormatOpenAPI {
+		return map[string]any{
+			"openapi": "3.0.3",
+			"info": map[string]any{
+				"title":   title,
+				"version": version,
+			},
+			"paths": map[string]any{},
+			"components": map[string]any{
+				"schemas": g.defs,
+			},
+		}
+	}
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   title,
+		"$defs":   g.defs,
+	}
+}
+
+func (g *Generator) nullable(s *Schema) *Schema {
+	if g.format == FormatOpenAPI {
+		if s.Ref != "" {
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	}
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+func (g *Generator) typeName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for _, v := range g.names {
+		if v == name {
+			// name collision between packages
+			pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+			name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+			break
+		}
+	}
+	g.names[t] = name
+	return name
+}
+
+func (g *Generator) named(t reflect.Type, mk func() *Schema) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.typeName(t)
+		g.defs[name] = &Schema{} // placeholder to handle recursive types
+		g.defs[name] = mk()
+	}
+	return g.Ref(name)
+}
+
+func (g *Generator) schemaOf(t reflect.Type) *Schema {
+	if fn, ok := g.custom[t]; ok {
+		return g.named(t, func() *Schema { return fn(g) })
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		if fn, ok := g.custom[t.Elem()]; ok {
+			return g.named(t.Elem(), func() *Schema { return fn(g) })
+		}
+		return g.schemaOf(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.named(t, func() *Schema { return g.structSchema(t) })
+	}
+	return &Schema{}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	ans := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addStructFields(t, ans)
+	return ans
+}
+
+func (g *Generator) addStructFields(t reflect.Type, out *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		tag := fld.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if fld.Anonymous && name == "" {
+			ft := fld.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addStructFields(ft, out)
+				continue
+			}
+		}
+		if !fld.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = fld.Name
+		}
+		fs := g.schemaOf(fld.Type)
+		if fld.Type.Kind() == reflect.Pointer && !strings.Contains(opts, "omitempty") {
+			fs = g.nullable(fs)
+		}
+		out.Properties[name] = fs
+		if !strings.Contains(opts, "omitempty") {
+			out.Required = append(out.Required, name)
+		}
+	}
+}
+
+// NewGenerator creates a generator with no custom schemas registered.
+// For the library types, use NewDefaultGenerator.
+func NewGenerator(format Format) *Generator {
+	return &Generator{
+		format: format,
+		defs:   make(map[string]*Schema),
+		names:  make(map[reflect.Type]string),
+		custom: make(map[reflect.Type]CustomSchemaFn),
+	}
+}
+
+func typeOf(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Interface {
+		return t.Elem()
+	}
+	return t
+}
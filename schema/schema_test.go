@@ -0,0 +1,119 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func collectRefs(s *Schema, out *[]string) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		*out = append(*out, s.Ref)
+	}
+	for _, v := range s.Properties {
+		collectRefs(v, out)
+	}
+	collectRefs(s.Items, out)
+	collectRefs(s.AdditionalProperties, out)
+	for _, items := range [][]*Schema{s.OneOf, s.AnyOf, s.AllOf} {
+		for _, v := range items {
+			collectRefs(v, out)
+		}
+	}
+}
+
+func TestAllRefsResolvable(t *testing.T) {
+	for _, format := range []Format{FormatOpenAPI, FormatJSONSchema} {
+		g := NewDefaultGenerator(format)
+		for _, v := range SharedTypes() {
+			g.Add(v)
+		}
+		var refs []string
+		for _, s := range g.Definitions() {
+			collectRefs(s, &refs)
+		}
+		assert.NotEmpty(t, refs)
+		for _, ref := range refs {
+			assert.True(t, strings.HasPrefix(ref, format.refPrefix()))
+			_, ok := g.Definitions()[strings.TrimPrefix(ref, format.refPrefix())]
+			assert.True(t, ok, "unresolvable ref %s", ref)
+		}
+	}
+}
+
+func TestLineElementDiscriminator(t *testing.T) {
+	g := NewDefaultGenerator(FormatOpenAPI)
+	g.Add(concordance.Line{})
+	defs := g.Definitions()
+	assert.Equal(t, "#/components/schemas/LineElement", defs["Line"].Properties["text"].Items.Ref)
+	assert.Equal(t, "type", defs["LineElement"].Discriminator.PropertyName)
+	assert.Equal(t, "structureType", defs["Markup"].Discriminator.PropertyName)
+	assert.Equal(
+		t,
+		"#/components/schemas/CloseStruct",
+		defs["Markup"].Discriminator.Mapping["close"],
+	)
+	assert.Equal(t, []any{"token"}, defs["Token"].Properties["type"].Enum)
+}
+
+func TestJSONSchemaHasNoDiscriminator(t *testing.T) {
+	g := NewDefaultGenerator(FormatJSONSchema)
+	g.Add(concordance.Line{})
+	assert.Nil(t, g.Definitions()["LineElement"].Discriminator)
+	assert.Len(t, g.Definitions()["LineElement"].OneOf, 2)
+}
+
+func TestNullablePointer(t *testing.T) {
+	g := NewDefaultGenerator(FormatJSONSchema)
+	g.Add(corp.Overview{})
+	citation := g.Definitions()["Overview"].Properties["citationInfo"]
+	assert.Len(t, citation.AnyOf, 2)
+	assert.Equal(t, "#/$defs/Citation", citation.AnyOf[0].Ref)
+	assert.Equal(t, "null", citation.AnyOf[1].Type)
+}
+
+func TestOmitEmptyNotRequired(t *testing.T) {
+	g := NewDefaultGenerator(FormatOpenAPI)
+	g.Add(corp.CorpusSetup{})
+	req := g.Definitions()["CorpusSetup"].Required
+	assert.Contains(t, req, "id")
+	assert.NotContains(t, req, "size")
+}
+
+func TestInvalidFormat(t *testing.T) {
+	_, err := Generate(Format("foo"), "test", "1.0")
+	assert.Error(t, err)
+}
+
+func TestTagsetNotEnum(t *testing.T) {
+	g := NewDefaultGenerator(FormatOpenAPI)
+	g.Add(corp.CorpusSetup{})
+	ts, ok := g.Definitions()["SupportedTagset"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "string", ts.Type)
+		assert.Nil(t, ts.Enum)
+		assert.Contains(t, ts.Description, string(corp.TagsetCSCNC2020))
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// SharedTypes lists the top-level types we publish schemas for.
+// All the types they depend on are exported too.
+func SharedTypes() []any {
+	return []any{
+		concordance.Line{},
+		corp.Overview{},
+		corp.CorpusSetup{},
+	}
+}
+
+func enumOf(values ...any) *Schema {
+	return &Schema{Type: "string", Enum: values}
+}
+
+func stringMap() *Schema {
+	return &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}
+}
+
+// tokenSchema describes the output of concordance.Token.MarshalJSON
+func tokenSchema(g *Generator) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"type":      enumOf("token"),
+			"word":      {Type: "string"},
+			"strong":    {Type: "boolean"},
+			"matchType": enumOf(concordance.MatchTypeKWIC, concordance.MatchTypeColl),
+			"attrs":     stringMap(),
+			"features": {
+				Type:                 "object",
+				AdditionalProperties: g.Add(&concordance.TokenFeature{}),
+			},
+			"errMsg": {Type: "string"},
+		},
+		Required: []string{"type", "word", "strong", "attrs"},
+	}
+}
+
+// structSchema describes the output of concordance.Struct.MarshalJSON
+func structSchema(g *Generator) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"type":          enumOf("markup"),
+			"structureType": enumOf("open", "self-close"),
+			"name":          {Type: "string"},
+			"error":         {Type: "string"},
+			"attrs":         stringMap(),
+		},
+		Required: []string{"type", "structureType", "name"},
+	}
+}
+
+// closeStructSchema describes the output of concordance.CloseStruct.MarshalJSON
+func closeStructSchema(g *Generator) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"type":          enumOf("markup"),
+			"structureType": enumOf("close"),
+			"name":          {Type: "string"},
+			// the value is a marshaled Go error which typically
+			// results in an empty object
+			"error": {Type: "object"},
+		},
+		Required: []string{"type", "structureType", "name"},
+	}
+}
+
+// ttSelectionSchema describes the output of corp.TTSelection.MarshalJSON
+// which produces a plain list of values for plain selections
+func ttSelectionSchema(g *Generator) *Schema {
+	return &Schema{
+		OneOf: []*Schema{
+			{Type: "array", Items: &Schema{Type: "string"}},
+			{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"values":  {Type: "array", Items: &Schema{Type: "string"}},
+					"regexp":  {Type: "string"},
+					"range":   g.Add(&corp.NumRange{}),
+					"negated": {Type: "boolean"},
+				},
+			},
+		},
+	}
+}
+
+// markupSchema is an intermediate node of the LineElement hierarchy
+// distinguishing between structures by their `structureType`.
+// We need it because OpenAPI discriminators cannot work
+// with more than one property.
+func markupSchema(g *Generator) *Schema {
+	structRef := g.Add(&concordance.Struct{})
+	closeRef := g.Add(&concordance.CloseStruct{})
+	ans := &Schema{
+		OneOf: []*Schema{structRef, closeRef},
+	}
+	if g.format == FormatOpenAPI {
+		ans.Discriminator = &Discriminator{
+			PropertyName: "structureType",
+			Mapping: map[string]string{
+				"open":       structRef.Ref,
+				"self-close": structRef.Ref,
+				"close":      closeRef.Ref,
+			},
+		}
+	}
+	return ans
+}
+
+func lineElementSchema(g *Generator) *Schema {
+	tokenRef := g.Add(&concordance.Token{})
+	markupRef := g.Ref("Markup")
+	if _, ok := g.defs["Markup"]; !ok {
+		g.defs["Markup"] = markupSchema(g)
+	}
+	ans := &Schema{
+		OneOf: []*Schema{tokenRef, markupRef},
+	}
+	if g.format == FormatOpenAPI {
+		ans.Discriminator = &Discriminator{
+			PropertyName: "type",
+			Mapping: map[string]string{
+				"token":  tokenRef.Ref,
+				"markup": markupRef.Ref,
+			},
+		}
+	}
+	return ans
+}
+
+// tagsetSchema describes corp.SupportedTagset. Tagsets can be loaded
+// at runtime (see tagset.Registry) so any identifier is accepted and
+// the built-in ones are just listed in the description.
+func tagsetSchema(g *Generator) *Schema {
+	return &Schema{
+		Type: "string",
+		Description: fmt.Sprintf(
+			"tagset identifier; built-in tagsets: %s",
+			strings.Join([]string{
+				string(corp.TagsetCSCNC2000SPK), string(corp.TagsetCSCNC2000),
+				string(corp.TagsetCSCNC2020), string(corp.TagsetUD),
+			}, ", "),
+		),
+	}
+}
+
+// NewDefaultGenerator creates a generator with custom schemas registered
+// for all the library types the reflection cannot describe properly.
+func NewDefaultGenerator(format Format) *Generator {
+	g := NewGenerator(format)
+	g.Register(concordance.Token{}, tokenSchema)
+	g.Register(concordance.Struct{}, structSchema)
+	g.Register(concordance.CloseStruct{}, closeStructSchema)
+	g.Register((*concordance.LineElement)(nil), lineElementSchema)
+	g.Register(corp.TTSelection{}, ttSelectionSchema)
+	g.Register(corp.SupportedTagset(""), tagsetSchema)
+	return g
+}
+
+// Generate produces a complete schema document for all the SharedTypes.
+func Generate(format Format, title, version string) ([]byte, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	g := NewDefaultGenerator(format)
+	for _, t := range SharedTypes() {
+		g.Add(t)
+	}
+	return json.MarshalIndent(g.Document(title, version), "", "  ")
+}
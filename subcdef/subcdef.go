@@ -0,0 +1,225 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package subcdef reads and writes Manatee subcorpus definition files
+// (as used by the `mksubc` tool). Each definition consists of three lines:
+//
+//	=NAME
+//	    structure
+//	    attr="value1|value2" & attr2="value3"
+//
+// Subcorpus descriptions are not supported by the format so they are
+// stored as special comments (`# description[lang]: text`) preceding
+// the respective definition. Manatee ignores them as any other comment.
+//
+// Besides plain values, regular expressions (`attr="A.*"`) and negated
+// tests (`attr!="A"`) are supported (see corp.TTSelection). Numeric
+// ranges are written as equivalent regular expressions and they are
+// read back as regular expressions. Please note that a single definition
+// can constrain only one structure.
+package subcdef
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/czcorpus/mquery-common/cql"
+)
+
+// ParseError describes a problem found in a definition file.
+// Line is a one-based line number.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid subcorpus definition at line %d: %s", e.Line, e.Msg)
+}
+
+// ------
+
+var descriptionRegexp = regexp.MustCompile(`^#\s*description\[([a-zA-Z_-]+)\]:\s?(.*)$`)
+
+type readState int
+
+const (
+	expectName readState = iota
+	expectStruct
+	expectQuery
+)
+
+// selectionsToSubcorpus stores plain selections as text types
+// and the other ones as selections
+func selectionsToSubcorpus(sels corp.TTSelections, subc *corp.Subcorpus) {
+	subc.TextTypes = make(corp.TextTypes)
+	for attr, sel := range sels {
+		if sel.IsPlain() {
+			subc.TextTypes[attr] = sel.Values
+
+		} else {
+			if subc.Selections == nil {
+				subc.Selections = make(corp.TTSelections)
+			}
+			subc.Selections[attr] = sel
+		}
+	}
+}
+
+// Read parses subcorpus definitions. Empty lines and lines starting
+// with `#` are ignored except for description comments preceding
+// a definition (see the package documentation). The returned subcorpora
+// are identified by their names (which are also used as their IDs).
+// Plain values are stored as TextTypes, other tests (regular expressions,
+// negation) as Selections.
+func Read(r io.Reader) (map[string]corp.Subcorpus, error) {
+	ans := make(map[string]corp.Subcorpus)
+	scanner := bufio.NewScanner(r)
+	state := expectName
+	var name, structure string
+	var description map[string]string
+	var lineNum, nameLine int
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if srch := descriptionRegexp.FindStringSubmatch(line); srch != nil && state == expectName {
+				if description == nil {
+					description = make(map[string]string)
+				}
+				description[srch[1]] = srch[2]
+			}
+			continue
+		}
+		switch state {
+		case expectName:
+			if !strings.HasPrefix(line, "=") {
+				return nil, &ParseError{Line: lineNum, Msg: "expected `=NAME`"}
+			}
+			name = strings.TrimSpace(line[1:])
+			if name == "" {
+				return nil, &ParseError{Line: lineNum, Msg: "missing subcorpus name"}
+			}
+			if _, ok := ans[name]; ok {
+				return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("duplicate subcorpus %s", name)}
+			}
+			nameLine = lineNum
+			state = expectStruct
+		case expectStruct:
+			if strings.HasPrefix(line, "=") || strings.ContainsAny(line, " \t=\"") {
+				return nil, &ParseError{Line: lineNum, Msg: "expected a structure name"}
+			}
+			structure = line
+			state = expectQuery
+		case expectQuery:
+			if strings.HasPrefix(line, "=") {
+				return nil, &ParseError{Line: lineNum, Msg: "expected a query"}
+			}
+			sels, err := cql.SelectionsFromCQL(fmt.Sprintf("within <%s %s/>", structure, line))
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, Msg: err.Error()}
+			}
+			subc := corp.Subcorpus{ID: name, Description: description}
+			selectionsToSubcorpus(sels, &subc)
+			ans[name] = subc
+			description = nil
+			state = expectName
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if state != expectName {
+		return nil, &ParseError{Line: nameLine, Msg: fmt.Sprintf("incomplete definition of %s", name)}
+	}
+	return ans, nil
+}
+
+// ------
+
+// Definition converts text types of a subcorpus into a structure name
+// and a query as used in the definition files.
+func Definition(subc corp.Subcorpus) (string, string, error) {
+	clauses, err := cql.SelectionsClauses(subc.AllSelections())
+	if err != nil {
+		return "", "", err
+	}
+	if len(clauses) == 0 {
+		return "", "", fmt.Errorf("no text types defined")
+	}
+	if len(clauses) > 1 {
+		return "", "", fmt.Errorf("text types must refer to a single structure")
+	}
+	s := clauses[0].Query.Items[0].Items[0].(*cql.Structure)
+	return s.Name, s.Expr.String(), nil
+}
+
+func writeDescription(w io.Writer, description map[string]string) error {
+	langs := make([]string, 0, len(description))
+	for lang := range description {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		text := description[lang]
+		if strings.ContainsAny(text, "\r\n") {
+			return fmt.Errorf("multi-line description (%s) cannot be written", lang)
+		}
+		line := fmt.Sprintf("# description[%s]: %s", lang, text)
+		if !descriptionRegexp.MatchString(line) {
+			return fmt.Errorf("invalid description language `%s`", lang)
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// Write writes subcorpus definitions sorted by their names
+// (i.e. the map keys). Descriptions are written as comments
+// (see the package documentation).
+func Write(w io.Writer, subcorpora map[string]corp.Subcorpus) error {
+	names := make([]string, 0, len(subcorpora))
+	for k := range subcorpora {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	bw := bufio.NewWriter(w)
+	for i, name := range names {
+		if name == "" || strings.ContainsAny(name, "\r\n") {
+			return fmt.Errorf("invalid subcorpus name `%s`", name)
+		}
+		structure, query, err := Definition(subcorpora[name])
+		if err != nil {
+			return fmt.Errorf("failed to write subcorpus %s: %w", name, err)
+		}
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		if err := writeDescription(bw, subcorpora[name].Description); err != nil {
+			return fmt.Errorf("failed to write subcorpus %s: %w", name, err)
+		}
+		fmt.Fprintf(bw, "=%s\n\t%s\n\t%s\n", name, structure, query)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subcdef
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	subc := map[string]corp.Subcorpus{
+		"fiction": {
+			ID:          "fiction",
+			TextTypes:   corp.TextTypes{"doc.txtype": {"FIC: novels", "FIC: poetry"}},
+			Description: map[string]string{"en": "fiction only"},
+		},
+		"news2000": {
+			TextTypes: corp.TextTypes{"doc.txtype": {"NMG: news"}, "doc.pubyear": {"2000"}},
+		},
+	}
+	var buff strings.Builder
+	assert.NoError(t, Write(&buff, subc))
+	assert.Equal(
+		t,
+		"# description[en]: fiction only\n"+
+			"=fiction\n\tdoc\n\ttxtype=\"FIC: novels|FIC: poetry\"\n\n"+
+			"=news2000\n\tdoc\n\tpubyear=\"2000\" & txtype=\"NMG: news\"\n",
+		buff.String(),
+	)
+	back, err := Read(strings.NewReader(buff.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]corp.Subcorpus{
+		"fiction": {
+			ID:          "fiction",
+			TextTypes:   corp.TextTypes{"doc.txtype": {"FIC: novels", "FIC: poetry"}},
+			Description: map[string]string{"en": "fiction only"},
+		},
+		"news2000": {ID: "news2000", TextTypes: corp.TextTypes{"doc.txtype": {"NMG: news"}, "doc.pubyear": {"2000"}}},
+	}, back)
+}
+
+func TestReadManateeStyle(t *testing.T) {
+	src := `# spoken part
+=BNCspoken
+    text
+    type="spoken" | type=="spoken (demographic)"
+`
+	ans, err := Read(strings.NewReader(src))
+	assert.NoError(t, err)
+	assert.Equal(t, corp.TextTypes{"text.type": {"spoken", "spoken (demographic)"}}, ans["BNCspoken"].TextTypes)
+}
+
+func TestWriteAndReadSelections(t *testing.T) {
+	lo, hi := 1990, 1999
+	subc := map[string]corp.Subcorpus{
+		"nineties": {
+			TextTypes: corp.TextTypes{"doc.txtype": {"NMG: news"}},
+			Selections: corp.TTSelections{
+				"doc.author":  {Regexp: "Čapek.*"},
+				"doc.pubyear": {Range: &corp.NumRange{From: &lo, To: &hi}},
+				"doc.medium":  {Values: []string{"web"}, Negated: true},
+			},
+			Description: map[string]string{"en": "news from the nineties", "cs": "zprávy z devadesátých let"},
+		},
+	}
+	var buff strings.Builder
+	assert.NoError(t, Write(&buff, subc))
+	assert.True(
+		t,
+		strings.HasPrefix(
+			buff.String(),
+			"# description[cs]: zprávy z devadesátých let\n# description[en]: news from the nineties\n=nineties\n",
+		),
+	)
+	back, err := Read(strings.NewReader(buff.String()))
+	assert.NoError(t, err)
+	ans := back["nineties"]
+	assert.Equal(t, subc["nineties"].Description, ans.Description)
+	assert.Equal(t, corp.TextTypes{"doc.txtype": {"NMG: news"}}, ans.TextTypes)
+	assert.Equal(t, corp.TTSelection{Regexp: "Čapek.*"}, ans.Selections["doc.author"])
+	assert.Equal(t, corp.TTSelection{Values: []string{"web"}, Negated: true}, ans.Selections["doc.medium"])
+
+	// ranges are read back as regular expressions
+	pubyear := ans.Selections["doc.pubyear"]
+	assert.Nil(t, pubyear.Range)
+	assert.True(t, pubyear.Matches("1995"))
+	assert.False(t, pubyear.Matches("2000"))
+}
+
+func TestReadErrors(t *testing.T) {
+	cases := map[string]int{
+		"doc\n":                             1,
+		"=\n":                               1,
+		"=a\ndoc\n":                         1,
+		"=a\ndoc\ntxtype=\"A\" | x=\"B\"\n": 3,
+		"=a\ndoc\ntxtype=\"A\"\n=a\ndoc\nx=\"B\"": 4,
+		"=a\n=b\n":      2,
+		"=a\ndoc\n=b\n": 3,
+	}
+	for src, line := range cases {
+		_, err := Read(strings.NewReader(src))
+		if assert.Error(t, err, src) {
+			perr, ok := err.(*ParseError)
+			assert.True(t, ok)
+			assert.Equal(t, line, perr.Line, src)
+		}
+	}
+	_, err := Read(strings.NewReader("=a\ndoc\ntxtype=\"A\"\n=b\ndoc\nx=\"B\"\n"))
+	assert.NoError(t, err)
+}
+
+func TestWriteErrors(t *testing.T) {
+	var buff strings.Builder
+	assert.Error(t, Write(&buff, map[string]corp.Subcorpus{"a": {}}))
+	assert.Error(t, Write(&buff, map[string]corp.Subcorpus{
+		"a": {
+			TextTypes:   corp.TextTypes{"doc.txtype": {"A"}},
+			Description: map[string]string{"en": "first\nsecond"},
+		},
+	}))
+	assert.Error(t, Write(&buff, map[string]corp.Subcorpus{
+		"a": {TextTypes: corp.TextTypes{"doc.txtype": {"A"}, "text.author": {"B"}}},
+	}))
+	assert.Error(t, Write(&buff, map[string]corp.Subcorpus{
+		"a\nb": {TextTypes: corp.TextTypes{"doc.txtype": {"A"}}},
+	}))
+}
+
+func TestWriteExtendedSelections(t *testing.T) {
+	from, to := 1990, 2005
+	var buff strings.Builder
+	assert.NoError(t, Write(&buff, map[string]corp.Subcorpus{
+		"nineties": {Selections: corp.TTSelections{
+			"doc.pubyear": {Range: &corp.NumRange{From: &from, To: &to}},
+			"doc.txtype":  {Regexp: "FIC.*", Negated: true},
+		}},
+	}))
+	assert.Equal(
+		t,
+		"=nineties\n\tdoc\n\tpubyear=\"199[0-9]|200[0-5]\" & txtype!=\"FIC.*\"\n",
+		buff.String(),
+	)
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syntax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	dotKWICColor = "#f7d26b"
+)
+
+func escapeDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+// ToDOT exports the tree to the Graphviz DOT format. Each node is labeled
+// by its word followed by values of `labelAttrs` (one per line). The KWIC
+// is highlighted and nodes with parents outside of the line are connected
+// to a placeholder node by a dashed edge. Nodes of the same depth are placed
+// on the same rank and children are ordered by their position in the line.
+func (t *Tree) ToDOT(name string, labelAttrs ...string) string {
+	var ans strings.Builder
+	ans.WriteString(fmt.Sprintf("digraph \"%s\" {\n", escapeDOT(name)))
+	ans.WriteString("  ordering=out;\n")
+	ans.WriteString("  node [shape=box, style=rounded];\n")
+	ans.WriteString("  edge [arrowhead=none];\n")
+	for _, n := range t.Nodes {
+		label := make([]string, 0, len(labelAttrs)+1)
+		label = append(label, escapeDOT(n.Token.Word))
+		for _, attr := range labelAttrs {
+			label = append(label, escapeDOT(n.Token.Attr(attr)))
+		}
+		style := ""
+		if n.IsKWIC() {
+			style = fmt.Sprintf(", style=\"rounded,filled\", fillcolor=\"%s\"", dotKWICColor)
+		}
+		ans.WriteString(
+			fmt.Sprintf("  n%d [label=\"%s\"%s];\n", n.Index, strings.Join(label, `\n`), style))
+	}
+	for _, n := range t.Nodes {
+		if n.Parent > -1 {
+			ans.WriteString(fmt.Sprintf("  n%d -> n%d;\n", n.Parent, n.Index))
+
+		} else if n.ParentOutside {
+			ans.WriteString(
+				fmt.Sprintf("  ext%d [label=\"\", shape=point];\n  ext%d -> n%d [style=dashed];\n",
+					n.Index, n.Index, n.Index))
+		}
+	}
+	// nodes of the same depth share a rank so the tree is drawn
+	// level by level; `ordering=out` keeps children in the word order
+	byDepth := make([][]string, 0, 10)
+	for _, n := range t.Nodes {
+		d := t.Depth(n.Index)
+		for len(byDepth) <= d {
+			byDepth = append(byDepth, make([]string, 0, 5))
+		}
+		byDepth[d] = append(byDepth[d], fmt.Sprintf("n%d", n.Index))
+	}
+	for _, ids := range byDepth {
+		if len(ids) > 0 {
+			ans.WriteString(fmt.Sprintf("  { rank=same; %s; }\n", strings.Join(ids, "; ")))
+		}
+	}
+	ans.WriteString("}\n")
+	return ans.String()
+}
+
+// ------
+
+type ArcDirection string
+
+const (
+	ArcDirectionLeft  ArcDirection = "left"
+	ArcDirectionRight ArcDirection = "right"
+)
+
+// LayoutNode is a node of a layout in the word order
+type LayoutNode struct {
+	Index         int               `json:"index"`
+	Word          string            `json:"word"`
+	Attrs         map[string]string `json:"attrs"`
+	IsKWIC        bool              `json:"isKwic"`
+	IsRoot        bool              `json:"isRoot"`
+	ParentOutside bool              `json:"parentOutside"`
+}
+
+// Arc is a dependency arc drawn above words. Height is a level
+// of the arc (starting from 1) computed so that shorter arcs nested
+// within longer ones are drawn lower and thus non-crossing arcs never
+// intersect.
+type Arc struct {
+
+	// Head is an index of the parent node. For parents outside
+	// of the line, the value is -1 (parent to the left)
+	// or len(Nodes) (parent to the right).
+	Head int `json:"head"`
+
+	// Dependent is an index of the child node
+	Dependent int `json:"dependent"`
+
+	// Direction is the direction of the arrow from the head
+	// to the dependent
+	Direction ArcDirection `json:"direction"`
+	Height    int          `json:"height"`
+
+	// Outside is true if the head is outside of the line
+	Outside bool `json:"outside,omitempty"`
+}
+
+func (a Arc) span() (int, int) {
+	return min(a.Head, a.Dependent), max(a.Head, a.Dependent)
+}
+
+// Layout contains data needed to render a dependency tree
+// as arcs over a sentence (e.g. in an SVG)
+type Layout struct {
+	Nodes     []LayoutNode `json:"nodes"`
+	Arcs      []Arc        `json:"arcs"`
+	KWIC      int          `json:"kwic"`
+	MaxHeight int          `json:"maxHeight"`
+}
+
+// Layout creates layout data for the tree. Node attributes are
+// taken from the SyntaxConcordance.ResultAttrs the tree has been
+// built with.
+func (t *Tree) Layout() *Layout {
+	ans := &Layout{
+		Nodes: make([]LayoutNode, len(t.Nodes)),
+		Arcs:  make([]Arc, 0, len(t.Nodes)),
+		KWIC:  -1,
+	}
+	for i, n := range t.Nodes {
+		ans.Nodes[i] = LayoutNode{
+			Index:         n.Index,
+			Word:          n.Token.Word,
+			Attrs:         t.ResultValues(n.Index),
+			IsKWIC:        n.IsKWIC(),
+			IsRoot:        n.IsRoot(),
+			ParentOutside: n.ParentOutside,
+		}
+		if n.IsKWIC() && ans.KWIC == -1 {
+			ans.KWIC = n.Index
+		}
+		if n.IsRoot() {
+			continue
+		}
+		arc := Arc{Head: n.Parent, Dependent: n.Index}
+		if n.ParentOutside {
+			arc.Outside = true
+			arc.Head = -1
+			if n.ParentOffset > 0 {
+				arc.Head = len(t.Nodes)
+			}
+		}
+		arc.Direction = ArcDirectionRight
+		if arc.Head > arc.Dependent {
+			arc.Direction = ArcDirectionLeft
+		}
+		ans.Arcs = append(ans.Arcs, arc)
+	}
+	sort.SliceStable(ans.Arcs, func(i, j int) bool {
+		li, ri := ans.Arcs[i].span()
+		lj, rj := ans.Arcs[j].span()
+		return ri-li < rj-lj
+	})
+	for i := range ans.Arcs {
+		left, right := ans.Arcs[i].span()
+		height := 1
+		for j := 0; j < i; j++ {
+			l, r := ans.Arcs[j].span()
+			if l >= left && r <= right {
+				height = max(height, ans.Arcs[j].Height+1)
+			}
+		}
+		ans.Arcs[i].Height = height
+		ans.MaxHeight = max(ans.MaxHeight, height)
+	}
+	sort.SliceStable(ans.Arcs, func(i, j int) bool {
+		return ans.Arcs[i].Dependent < ans.Arcs[j].Dependent
+	})
+	return ans
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syntax
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDOT(t *testing.T) {
+	dot := testTree(t).ToDOT(`line "1"`, "lemma")
+	assert.True(t, strings.HasPrefix(dot, "digraph \"line \\\"1\\\"\" {\n"))
+	assert.Contains(t, dot, `n3 [label="provoz\nprovoz", style="rounded,filled", fillcolor="#f7d26b"];`)
+	assert.Contains(t, dot, "  n1 -> n3;\n")
+	assert.Contains(t, dot, "  ext1 -> n1 [style=dashed];\n")
+	assert.NotContains(t, dot, "-> n6;")
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+}
+
+func TestToDOTRanks(t *testing.T) {
+	tree := testTree(t)
+	dot := tree.ToDOT("line")
+	assert.NotContains(t, dot, "rank=max")
+	assert.Contains(t, dot, "  ordering=out;\n")
+
+	// each node must be in exactly one rank group
+	rankOf := make(map[string]int)
+	groups := regexp.MustCompile(`\{ rank=same; ([^}]+); \}`).FindAllStringSubmatch(dot, -1)
+	for i, g := range groups {
+		for _, id := range strings.Split(g[1], "; ") {
+			_, ok := rankOf[id]
+			assert.False(t, ok, id)
+			rankOf[id] = i
+		}
+	}
+	assert.Len(t, rankOf, len(tree.Nodes))
+	assert.Greater(t, len(groups), 1)
+
+	// children must be placed below their parents
+	edges := regexp.MustCompile(`(?m)^  (n\d+) -> (n\d+);$`).FindAllStringSubmatch(dot, -1)
+	assert.Len(t, edges, 5)
+	for _, e := range edges {
+		assert.Equal(t, rankOf[e[1]]+1, rankOf[e[2]], "%s -> %s", e[1], e[2])
+	}
+}
+
+func TestLayout(t *testing.T) {
+	layout := testTree(t).Layout()
+	assert.Len(t, layout.Nodes, 7)
+	assert.Equal(t, 3, layout.KWIC)
+	assert.True(t, layout.Nodes[3].IsKWIC)
+	assert.True(t, layout.Nodes[6].IsRoot)
+	assert.Equal(t, "zavádět", layout.Nodes[3].Attrs["p_lemma"])
+	assert.Len(t, layout.Arcs, 6)
+	assert.Equal(t, 2, layout.MaxHeight)
+
+	assert.Equal(t, Arc{Head: 1, Dependent: 0, Direction: ArcDirectionLeft, Height: 1}, layout.Arcs[0])
+	assert.Equal(
+		t,
+		Arc{Head: -1, Dependent: 1, Direction: ArcDirectionRight, Height: 2, Outside: true},
+		layout.Arcs[1],
+	)
+	assert.Equal(t, Arc{Head: 1, Dependent: 3, Direction: ArcDirectionRight, Height: 2}, layout.Arcs[3])
+	assert.Equal(t, Arc{Head: 3, Dependent: 5, Direction: ArcDirectionRight, Height: 2}, layout.Arcs[5])
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syntax
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// Relation specifies how a related node is connected to the KWIC
+type Relation string
+
+const (
+	// RelationParent means the related node is the KWIC's parent
+	RelationParent Relation = "parent"
+
+	// RelationChild means the related node is a KWIC's child
+	RelationChild Relation = "child"
+)
+
+func (r Relation) Validate() error {
+	if r == RelationParent || r == RelationChild {
+		return nil
+	}
+	return fmt.Errorf("invalid syntactic relation: %s", r)
+}
+
+// NodeConstraint maps attributes to regular expressions their
+// values must match. As in CQL, the expressions must match whole
+// values (e.g. "V.*" for verbal tags).
+type NodeConstraint map[string]string
+
+// Pattern describes lines we are looking for: the KWIC node
+// satisfying the KWIC constraint and a node in the specified relation
+// satisfying the Related constraint.
+type Pattern struct {
+	KWIC     NodeConstraint `json:"kwic"`
+	Relation Relation       `json:"relation"`
+	Related  NodeConstraint `json:"related"`
+}
+
+type compiledConstraint map[string]*regexp.Regexp
+
+func (cc compiledConstraint) match(n *Node) bool {
+	for attr, rx := range cc {
+		if !rx.MatchString(n.Token.Attr(attr)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ------
+
+// TokenPair is a pair of nodes satisfying a pattern
+type TokenPair struct {
+	KWIC    *Node `json:"kwic"`
+	Related *Node `json:"related"`
+}
+
+// PatternMatch contains all the matching pairs found in a line
+type PatternMatch struct {
+
+	// LineIdx is an index of the line within the processed batch
+	LineIdx int         `json:"lineIdx"`
+	Pairs   []TokenPair `json:"pairs"`
+}
+
+// Matcher searches for a syntactic pattern in concordance lines
+type Matcher struct {
+	conf     corp.SyntaxConcordance
+	relation Relation
+	kwic     compiledConstraint
+	related  compiledConstraint
+}
+
+// relatedNodes returns nodes in the matcher's relation to the node
+func relatedNodes(tree *Tree, n *Node, rel Relation) []*Node {
+	if rel == RelationParent {
+		if p := tree.ParentOf(n.Index); p != nil {
+			return []*Node{p}
+		}
+		return []*Node{}
+	}
+	return tree.ChildrenOf(n.Index)
+}
+
+// MatchTree returns all the KWIC-related node pairs of the tree
+// satisfying the pattern.
+func (m *Matcher) MatchTree(tree *Tree) []TokenPair {
+	ans := make([]TokenPair, 0, 2)
+	for _, n := range tree.Nodes {
+		if !n.IsKWIC() || !m.kwic.match(n) {
+			continue
+		}
+		for _, rn := range relatedNodes(tree, n, m.relation) {
+			if m.related.match(rn) {
+				ans = append(ans, TokenPair{KWIC: n, Related: rn})
+			}
+		}
+	}
+	return ans
+}
+
+// Match returns all the KWIC-related node pairs of the line
+// satisfying the pattern.
+func (m *Matcher) Match(line *concordance.Line) ([]TokenPair, error) {
+	tree, err := BuildTree(line, m.conf)
+	if err != nil {
+		return nil, err
+	}
+	return m.MatchTree(tree), nil
+}
+
+// Select returns matches of all the lines containing at least one
+// matching pair. Lines with broken syntax data are skipped
+// and their number is returned as the second value.
+func (m *Matcher) Select(lines []concordance.Line) ([]PatternMatch, int) {
+	ans := make([]PatternMatch, 0, len(lines))
+	var numSkipped int
+	for i := range lines {
+		pairs, err := m.Match(&lines[i])
+		if err != nil {
+			numSkipped++
+			continue
+		}
+		if len(pairs) > 0 {
+			ans = append(ans, PatternMatch{LineIdx: i, Pairs: pairs})
+		}
+	}
+	return ans, numSkipped
+}
+
+func validateResultAttr(conf corp.SyntaxConcordance, attr string) error {
+	for _, v := range conf.ResultAttrs {
+		if v == attr {
+			return nil
+		}
+	}
+	return fmt.Errorf("attribute %s is not among syntax result attributes", attr)
+}
+
+func compileConstraint(conf corp.SyntaxConcordance, c NodeConstraint) (compiledConstraint, error) {
+	ans := make(compiledConstraint, len(c))
+	for attr, expr := range c {
+		if err := validateResultAttr(conf, attr); err != nil {
+			return nil, err
+		}
+		rx, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint for %s: %w", attr, err)
+		}
+		ans[attr] = rx
+	}
+	return ans, nil
+}
+
+// NewMatcher creates a matcher for the pattern. All the constrained
+// attributes must be listed in the SyntaxConcordance.ResultAttrs.
+func NewMatcher(conf corp.SyntaxConcordance, pattern Pattern) (*Matcher, error) {
+	if err := pattern.Relation.Validate(); err != nil {
+		return nil, err
+	}
+	kwic, err := compileConstraint(conf, pattern.KWIC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create syntax matcher: %w", err)
+	}
+	related, err := compileConstraint(conf, pattern.Related)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create syntax matcher: %w", err)
+	}
+	return &Matcher{
+		conf:     conf,
+		relation: pattern.Relation,
+		kwic:     kwic,
+		related:  related,
+	}, nil
+}
+
+// ------
+
+// RelationFreq is a frequency of a relation pattern, i.e. a combination
+// of the relation type and attribute values of both the KWIC and the related
+// node.
+type RelationFreq struct {
+	Relation      Relation `json:"relation"`
+	KWICValues    []string `json:"kwicValues"`
+	RelatedValues []string `json:"relatedValues"`
+	Freq          int      `json:"freq"`
+
+	// Lines contains indices of lines the pattern has been found in
+	Lines []int `json:"lines"`
+}
+
+// RelationStats is a word-sketch-like overview of syntactic relations
+// of KWICs in a batch of concordance lines.
+type RelationStats struct {
+	Freqs []*RelationFreq `json:"freqs"`
+
+	// NumSkipped is the number of lines with broken syntax data
+	NumSkipped int `json:"numSkipped"`
+}
+
+// AggregateOptions specifies how relation patterns are grouped.
+// All the attributes must be listed in the SyntaxConcordance.ResultAttrs.
+type AggregateOptions struct {
+	KWICAttrs    []string
+	RelatedAttrs []string
+
+	// Matcher, if set, restricts counted pairs to the matching ones
+	Matcher *Matcher
+}
+
+func nodeValues(n *Node, attrs []string) []string {
+	ans := make([]string, len(attrs))
+	for i, attr := range attrs {
+		ans[i] = n.Token.Attr(attr)
+	}
+	return ans
+}
+
+// AggregateRelations counts KWIC's parent and child relations across
+// the lines, grouped by values of the attributes specified in `opts`.
+// The result is sorted by frequencies (descending).
+func AggregateRelations(
+	lines []concordance.Line,
+	conf corp.SyntaxConcordance,
+	opts AggregateOptions,
+) (*RelationStats, error) {
+	for _, attr := range append(append([]string{}, opts.KWICAttrs...), opts.RelatedAttrs...) {
+		if err := validateResultAttr(conf, attr); err != nil {
+			return nil, fmt.Errorf("failed to aggregate relations: %w", err)
+		}
+	}
+	ans := &RelationStats{Freqs: make([]*RelationFreq, 0, 50)}
+	index := make(map[string]*RelationFreq)
+	add := func(lineIdx int, rel Relation, kwic, related *Node) {
+		kv := nodeValues(kwic, opts.KWICAttrs)
+		rv := nodeValues(related, opts.RelatedAttrs)
+		key := string(rel) + "\x00" + strings.Join(kv, "\x1f") + "\x00" + strings.Join(rv, "\x1f")
+		item, ok := index[key]
+		if !ok {
+			item = &RelationFreq{Relation: rel, KWICValues: kv, RelatedValues: rv}
+			index[key] = item
+			ans.Freqs = append(ans.Freqs, item)
+		}
+		item.Freq++
+		if len(item.Lines) == 0 || item.Lines[len(item.Lines)-1] != lineIdx {
+			item.Lines = append(item.Lines, lineIdx)
+		}
+	}
+	for i := range lines {
+		tree, err := BuildTree(&lines[i], conf)
+		if err != nil {
+			ans.NumSkipped++
+			continue
+		}
+		for _, rel := range []Relation{RelationParent, RelationChild} {
+			if opts.Matcher != nil && opts.Matcher.relation != rel {
+				continue
+			}
+			for _, n := range tree.Nodes {
+				if !n.IsKWIC() {
+					continue
+				}
+				for _, rn := range relatedNodes(tree, n, rel) {
+					if opts.Matcher != nil && (!opts.Matcher.kwic.match(n) || !opts.Matcher.related.match(rn)) {
+						continue
+					}
+					add(i, rel, n, rn)
+				}
+			}
+		}
+	}
+	sort.SliceStable(ans.Freqs, func(i, j int) bool {
+		return ans.Freqs[i].Freq > ans.Freqs[j].Freq
+	})
+	return ans, nil
+}
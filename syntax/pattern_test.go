@@ -0,0 +1,126 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syntax
+
+import (
+	"testing"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+var patternTestConf = corp.SyntaxConcordance{
+	ParentAttr:  "parent",
+	ResultAttrs: []string{"lemma", "tag"},
+}
+
+func patternTestLines() []concordance.Line {
+	p := concordance.NewLineParser([]string{"word", "lemma", "tag", "parent"})
+	return p.Parse([]string{
+		`#1 {refs:end}Petr {} /Petr/NNMS1/+1 attr  vidí {} /vidět/VB-S/0 attr` +
+			`  psa {col0 coll} /pes/NNMS4/-1 attr`,
+		`#2 {refs:end}velký {} /velký/AAMS1/+1 attr  pes {col0 coll} /pes/NNMS1/+1 attr` +
+			`  štěká {} /štěkat/VB-S/0 attr`,
+		`#3 {refs:end}psa {col0 coll} /pes/NNMS4/-1 attr  . {} /./Z:/0 attr`,
+		`#4 {refs:end}psa {col0 coll} /pes/NNMS4/x attr`,
+		`#5 {refs:end}Pavel {} /Pavel/NNMS1/+1 attr  vidí {} /vidět/VB-S/0 attr` +
+			`  velkého {} /velký/AAMS4/+1 attr  psa {col0 coll} /pes/NNMS4/-2 attr`,
+	})
+}
+
+func TestMatcherParentVerb(t *testing.T) {
+	m, err := NewMatcher(
+		patternTestConf,
+		Pattern{KWIC: NodeConstraint{"tag": "NN.*"}, Relation: RelationParent, Related: NodeConstraint{"tag": "V.*"}},
+	)
+	assert.NoError(t, err)
+	matches, skipped := m.Select(patternTestLines())
+	assert.Equal(t, 1, skipped)
+	assert.Len(t, matches, 3)
+	assert.Equal(t, 0, matches[0].LineIdx)
+	assert.Equal(t, "vidí", matches[0].Pairs[0].Related.Token.Word)
+	assert.Equal(t, "psa", matches[0].Pairs[0].KWIC.Token.Word)
+	assert.Equal(t, 1, matches[1].LineIdx)
+	assert.Equal(t, 4, matches[2].LineIdx)
+}
+
+func TestMatcherChild(t *testing.T) {
+	m, err := NewMatcher(
+		patternTestConf,
+		Pattern{KWIC: NodeConstraint{"tag": "NNMS4"}, Relation: RelationChild, Related: NodeConstraint{"tag": "A.*"}},
+	)
+	assert.NoError(t, err)
+	matches, _ := m.Select(patternTestLines())
+	assert.Len(t, matches, 1)
+	assert.Equal(t, 4, matches[0].LineIdx)
+	assert.Equal(t, "velkého", matches[0].Pairs[0].Related.Token.Word)
+}
+
+func TestMatcherConstraintIsAnchored(t *testing.T) {
+	m, err := NewMatcher(
+		patternTestConf,
+		Pattern{Relation: RelationParent, Related: NodeConstraint{"lemma": "vid"}},
+	)
+	assert.NoError(t, err)
+	matches, _ := m.Select(patternTestLines())
+	assert.Len(t, matches, 0)
+}
+
+func TestNewMatcherErrors(t *testing.T) {
+	_, err := NewMatcher(patternTestConf, Pattern{Relation: "sibling"})
+	assert.Error(t, err)
+	_, err = NewMatcher(patternTestConf, Pattern{Relation: RelationChild, KWIC: NodeConstraint{"word": "x"}})
+	assert.Error(t, err)
+	_, err = NewMatcher(patternTestConf, Pattern{Relation: RelationChild, Related: NodeConstraint{"tag": "[x"}})
+	assert.Error(t, err)
+}
+
+func TestAggregateRelations(t *testing.T) {
+	stats, err := AggregateRelations(
+		patternTestLines(),
+		patternTestConf,
+		AggregateOptions{KWICAttrs: []string{"lemma"}, RelatedAttrs: []string{"lemma"}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.NumSkipped)
+	assert.Len(t, stats.Freqs, 3)
+	top := stats.Freqs[0]
+	assert.Equal(t, RelationParent, top.Relation)
+	assert.Equal(t, []string{"pes"}, top.KWICValues)
+	assert.Equal(t, []string{"vidět"}, top.RelatedValues)
+	assert.Equal(t, 2, top.Freq)
+	assert.Equal(t, []int{0, 4}, top.Lines)
+}
+
+func TestAggregateRelationsWithMatcher(t *testing.T) {
+	m, err := NewMatcher(patternTestConf, Pattern{Relation: RelationChild})
+	assert.NoError(t, err)
+	stats, err := AggregateRelations(
+		patternTestLines(),
+		patternTestConf,
+		AggregateOptions{RelatedAttrs: []string{"lemma"}, Matcher: m},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, stats.Freqs, 1)
+	assert.Equal(t, RelationChild, stats.Freqs[0].Relation)
+	assert.Equal(t, []string{"velký"}, stats.Freqs[0].RelatedValues)
+	assert.Equal(t, 2, stats.Freqs[0].Freq)
+
+	_, err = AggregateRelations(patternTestLines(), patternTestConf, AggregateOptions{KWICAttrs: []string{"foo"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,301 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package syntax reconstructs dependency trees from concordance
+// lines of corpora with syntax annotation (see corp.SyntaxConcordance).
+package syntax
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// CycleError is returned in case parent references form a cycle
+type CycleError struct {
+	// Nodes contains indices of nodes forming the cycle
+	Nodes []int
+}
+
+func (e *CycleError) Error() string {
+	items := make([]string, len(e.Nodes))
+	for i, v := range e.Nodes {
+		items[i] = strconv.Itoa(v)
+	}
+	return fmt.Sprintf("dependency cycle found: %s", strings.Join(items, " -> "))
+}
+
+// MultipleRootsError reports a tree with more than one root
+// (typically a context spanning multiple sentences).
+type MultipleRootsError struct {
+	Roots []int
+}
+
+func (e *MultipleRootsError) Error() string {
+	return fmt.Sprintf("multiple roots found: %v", e.Roots)
+}
+
+// ------
+
+// Node is a single token within a dependency tree
+type Node struct {
+
+	// Index is a position of the token among line tokens
+	// (i.e. structures are not counted)
+	Index int `json:"index"`
+
+	Token *concordance.Token `json:"token"`
+
+	// Parent is an index of the parent node. For roots and nodes
+	// with parents outside of the line, the value is -1.
+	Parent int `json:"parent"`
+
+	// ParentOffset is the raw relative position of the parent
+	// as stored in the corpus.
+	ParentOffset int `json:"parentOffset"`
+
+	// ParentOutside is true if the parent exists but it is not
+	// part of the (visible) line
+	ParentOutside bool `json:"parentOutside,omitempty"`
+
+	// Children contains indices of child nodes (ordered)
+	Children []int `json:"children"`
+}
+
+// IsRoot returns true if the node is a real root of a sentence
+// (as opposed to a node whose parent is just not visible).
+func (n *Node) IsRoot() bool {
+	return n.ParentOffset == 0
+}
+
+func (n *Node) IsKWIC() bool {
+	return n.Token.MatchType == concordance.MatchTypeKWIC
+}
+
+// ------
+
+// Tree is a dependency tree (or a forest in case of multiple
+// sentences or parents outside of the line) built from a single
+// concordance line.
+type Tree struct {
+	Nodes []*Node `json:"nodes"`
+
+	// Roots contains indices of sentence roots
+	Roots []int `json:"roots"`
+
+	// Detached contains indices of nodes whose parents are outside
+	// of the line. Together with Roots, these are the tops
+	// of all the subtrees in the line.
+	Detached []int `json:"detached"`
+
+	resultAttrs []string
+}
+
+// Node returns a node at the specified index or nil
+// in case the index is out of range.
+func (t *Tree) Node(idx int) *Node {
+	if idx < 0 || idx >= len(t.Nodes) {
+		return nil
+	}
+	return t.Nodes[idx]
+}
+
+// KWIC returns the node of the first KWIC token or nil if there is no KWIC
+func (t *Tree) KWIC() *Node {
+	for _, n := range t.Nodes {
+		if n.IsKWIC() {
+			return n
+		}
+	}
+	return nil
+}
+
+// ParentOf returns the parent of the node or nil in case the node
+// is a root or its parent is not available.
+func (t *Tree) ParentOf(idx int) *Node {
+	n := t.Node(idx)
+	if n == nil {
+		return nil
+	}
+	return t.Node(n.Parent)
+}
+
+// ChildrenOf returns direct children of a node
+func (t *Tree) ChildrenOf(idx int) []*Node {
+	n := t.Node(idx)
+	if n == nil {
+		return nil
+	}
+	ans := make([]*Node, len(n.Children))
+	for i, c := range n.Children {
+		ans[i] = t.Nodes[c]
+	}
+	return ans
+}
+
+// Subtree returns the node and all its descendants ordered
+// by their position in the line.
+func (t *Tree) Subtree(idx int) []*Node {
+	if t.Node(idx) == nil {
+		return nil
+	}
+	inSubtree := make([]bool, len(t.Nodes))
+	stack := []int{idx}
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		inSubtree[curr] = true
+		stack = append(stack, t.Nodes[curr].Children...)
+	}
+	ans := make([]*Node, 0, len(t.Nodes))
+	for i, v := range inSubtree {
+		if v {
+			ans = append(ans, t.Nodes[i])
+		}
+	}
+	return ans
+}
+
+// PathToRoot returns the node and all its ancestors up to the root
+// (or up to the top-most visible ancestor).
+func (t *Tree) PathToRoot(idx int) []*Node {
+	ans := make([]*Node, 0, 10)
+	for n := t.Node(idx); n != nil; n = t.Node(n.Parent) {
+		ans = append(ans, n)
+	}
+	return ans
+}
+
+// Depth returns the number of visible ancestors of a node
+func (t *Tree) Depth(idx int) int {
+	return max(0, len(t.PathToRoot(idx))-1)
+}
+
+// ResultValues returns values of the attributes configured
+// in SyntaxConcordance.ResultAttrs for a node.
+func (t *Tree) ResultValues(idx int) map[string]string {
+	n := t.Node(idx)
+	if n == nil {
+		return nil
+	}
+	ans := make(map[string]string, len(t.resultAttrs))
+	for _, attr := range t.resultAttrs {
+		ans[attr] = n.Token.Attr(attr)
+	}
+	return ans
+}
+
+// CheckSingleRoot returns *MultipleRootsError in case the tree
+// contains more than one sentence root.
+func (t *Tree) CheckSingleRoot() error {
+	if len(t.Roots) > 1 {
+		return &MultipleRootsError{Roots: t.Roots}
+	}
+	return nil
+}
+
+func (t *Tree) findCycle() []int {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make([]int, len(t.Nodes))
+	for i := range t.Nodes {
+		if state[i] != unvisited {
+			continue
+		}
+		path := make([]int, 0, 10)
+		curr := i
+		for curr != -1 && state[curr] == unvisited {
+			state[curr] = inProgress
+			path = append(path, curr)
+			curr = t.Nodes[curr].Parent
+		}
+		if curr != -1 && state[curr] == inProgress {
+			for j, v := range path {
+				if v == curr {
+					return append(path[j:], curr)
+				}
+			}
+		}
+		for _, v := range path {
+			state[v] = done
+		}
+	}
+	return nil
+}
+
+// BuildTree creates a dependency tree from a concordance line using
+// the SyntaxConcordance.ParentAttr attribute which is expected to contain
+// relative positions of parent tokens (e.g. "-2", "+1") with "0" for roots.
+func BuildTree(line *concordance.Line, conf corp.SyntaxConcordance) (*Tree, error) {
+	if conf.ParentAttr == "" {
+		return nil, fmt.Errorf("failed to build syntax tree: parent attribute not configured")
+	}
+	tokens := line.Text.Tokens()
+	ans := &Tree{
+		Nodes:       make([]*Node, len(tokens)),
+		Roots:       make([]int, 0, 1),
+		Detached:    make([]int, 0, 5),
+		resultAttrs: conf.ResultAttrs,
+	}
+	for i, tok := range tokens {
+		rawOffset, ok := tok.Attrs[conf.ParentAttr]
+		if !ok {
+			return nil, fmt.Errorf(
+				"failed to build syntax tree: token %d (%s) has no attribute %s",
+				i, tok.Word, conf.ParentAttr)
+		}
+		offset, err := strconv.Atoi(rawOffset)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to build syntax tree: invalid parent value `%s` of token %d (%s)",
+				rawOffset, i, tok.Word)
+		}
+		node := &Node{
+			Index:        i,
+			Token:        tok,
+			Parent:       -1,
+			ParentOffset: offset,
+			Children:     []int{},
+		}
+		if offset == 0 {
+			ans.Roots = append(ans.Roots, i)
+
+		} else if i+offset < 0 || i+offset >= len(tokens) {
+			node.ParentOutside = true
+			ans.Detached = append(ans.Detached, i)
+
+		} else {
+			node.Parent = i + offset
+		}
+		ans.Nodes[i] = node
+	}
+	for _, node := range ans.Nodes {
+		if node.Parent > -1 {
+			parent := ans.Nodes[node.Parent]
+			parent.Children = append(parent.Children, node.Index)
+		}
+	}
+	if cycle := ans.findCycle(); cycle != nil {
+		return nil, &CycleError{Nodes: cycle}
+	}
+	return ans, nil
+}
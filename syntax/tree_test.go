@@ -0,0 +1,129 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syntax
+
+import (
+	"testing"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// the same line as used in concordance tests
+	tl1 = `#75308554 {refs:end} ` +
+		`která {} /který/zavádět/+1 attr  zavádí {} /zavádět/země/-5 attr  ` +
+		`celoplošný {} /celoplošný/provoz/+1 attr provoz {col0 coll} /provoz/zavádět/-2 attr ` +
+		`těchto {} /tento/služba/+1 attr  služeb {} /služba/provoz/-2 attr  . {} /.//0 attr`
+
+	tlCycle = `#1 {refs:end} a {} /a/+1 attr  b {col0 coll} /b/+1 attr  c {} /c/-1 attr`
+
+	tlTwoSentences = `#2 {refs:end} a {} /a/0 attr  . {col0 coll} /./-1 attr  b {} /b/0 attr`
+)
+
+var testConf = corp.SyntaxConcordance{
+	ParentAttr:  "parent",
+	ResultAttrs: []string{"lemma", "p_lemma"},
+}
+
+func parseLine(src string, attrs ...string) *concordance.Line {
+	p := concordance.NewLineParser(attrs)
+	line := p.ParseLine(src)
+	return &line
+}
+
+func testTree(t *testing.T) *Tree {
+	tree, err := BuildTree(parseLine(tl1, "word", "lemma", "p_lemma", "parent"), testConf)
+	assert.NoError(t, err)
+	return tree
+}
+
+func words(nodes []*Node) []string {
+	ans := make([]string, len(nodes))
+	for i, n := range nodes {
+		ans[i] = n.Token.Word
+	}
+	return ans
+}
+
+func TestBuildTree(t *testing.T) {
+	tree := testTree(t)
+	assert.Len(t, tree.Nodes, 7)
+	assert.Equal(t, []int{6}, tree.Roots)
+	assert.Equal(t, []int{1}, tree.Detached)
+	assert.True(t, tree.Nodes[1].ParentOutside)
+	assert.Equal(t, -5, tree.Nodes[1].ParentOffset)
+	assert.Equal(t, "provoz", tree.KWIC().Token.Word)
+	assert.NoError(t, tree.CheckSingleRoot())
+}
+
+func TestTraversal(t *testing.T) {
+	tree := testTree(t)
+	kwic := tree.KWIC()
+	assert.Equal(t, "zavádí", tree.ParentOf(kwic.Index).Token.Word)
+	assert.Equal(t, []string{"celoplošný", "služeb"}, words(tree.ChildrenOf(kwic.Index)))
+	assert.Equal(
+		t,
+		[]string{"celoplošný", "provoz", "těchto", "služeb"},
+		words(tree.Subtree(kwic.Index)),
+	)
+	assert.Equal(t, []string{"těchto", "služeb", "provoz", "zavádí"}, words(tree.PathToRoot(4)))
+	assert.Equal(t, 3, tree.Depth(4))
+	assert.Nil(t, tree.ParentOf(1))
+	assert.Nil(t, tree.Node(100))
+}
+
+func TestResultValues(t *testing.T) {
+	tree := testTree(t)
+	assert.Equal(
+		t,
+		map[string]string{"lemma": "provoz", "p_lemma": "zavádět"},
+		tree.ResultValues(tree.KWIC().Index),
+	)
+}
+
+func TestCycleDetection(t *testing.T) {
+	_, err := BuildTree(parseLine(tlCycle, "word", "lemma", "parent"), testConf)
+	assert.Error(t, err)
+	cycleErr, ok := err.(*CycleError)
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 2, 1}, cycleErr.Nodes)
+}
+
+func TestMultipleRoots(t *testing.T) {
+	tree, err := BuildTree(parseLine(tlTwoSentences, "word", "lemma", "parent"), testConf)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, tree.Roots)
+	_, ok := tree.CheckSingleRoot().(*MultipleRootsError)
+	assert.True(t, ok)
+}
+
+func TestBuildTreeInvalidInput(t *testing.T) {
+	_, err := BuildTree(parseLine(tl1, "word", "lemma", "p_lemma", "parent"), corp.SyntaxConcordance{})
+	assert.Error(t, err)
+	_, err = BuildTree(
+		parseLine(tl1, "word", "lemma", "p_lemma", "parent"),
+		corp.SyntaxConcordance{ParentAttr: "p_lemma"},
+	)
+	assert.Error(t, err)
+	_, err = BuildTree(
+		parseLine(tl1, "word", "lemma", "p_lemma", "parent"),
+		corp.SyntaxConcordance{ParentAttr: "foo"},
+	)
+	assert.Error(t, err)
+}
@@ -0,0 +1,375 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import "github.com/czcorpus/mquery-common/corp"
+
+// Definitions of the CNC positional tagsets. They are based
+// on the Prague positional tagset (as used in the PDT) with
+// the CNC specific modifications.
+
+func notApplicable() Value {
+	return Value{Code: NotApplicable, Labels: labels("not applicable", "neurčuje se")}
+}
+
+func anyValue() Value {
+	return Value{Code: "X", Labels: labels("any", "libovolný")}
+}
+
+func val(code, en, cs string, pos ...string) Value {
+	return Value{Code: code, Labels: labels(en, cs), RequiresPOS: pos}
+}
+
+func cncPOS() Position {
+	return Position{
+		ID:     "pos",
+		Labels: labels("part of speech", "slovní druh"),
+		Values: []Value{
+			val("A", "adjective", "adjektivum"),
+			val("C", "numeral", "číslovka"),
+			val("D", "adverb", "adverbium"),
+			val("I", "interjection", "citoslovce"),
+			val("J", "conjunction", "spojka"),
+			val("N", "noun", "substantivum"),
+			val("P", "pronoun", "zájmeno"),
+			val("R", "preposition", "předložka"),
+			val("T", "particle", "částice"),
+			val("V", "verb", "sloveso"),
+			val("X", "unknown", "neznámý"),
+			val("Z", "punctuation", "interpunkce"),
+		},
+	}
+}
+
+func cncSubPOS() Position {
+	return Position{
+		ID:     "subpos",
+		Labels: labels("detailed part of speech", "detailní slovní druh"),
+		Values: []Value{
+			val("!", "abbreviation used as an adverb", "zkratka užitá jako adverbium", "D"),
+			val("#", "sentence boundary", "hranice věty", "Z"),
+			val("%", "author's signature", "podpis autora", "Z"),
+			val("*", "word krát (times)", "slovo krát", "J"),
+			val(",", "subordinating conjunction", "podřadicí spojka", "J"),
+			val(".", "abbreviation used as an adjective", "zkratka užitá jako adjektivum", "A"),
+			val("0", "preposition with attached -ň", "předložka s připojeným -ň", "P"),
+			val("1", "relative possessive pronoun", "vztažné přivlastňovací zájmeno", "P"),
+			val("2", "hyphenated part of a compound adjective", "první část složeného adjektiva", "A"),
+			val("3", "abbreviation used as a numeral", "zkratka užitá jako číslovka", "C"),
+			val("4", "relative/interrogative pronoun with adjectival declension",
+				"vztažné/tázací zájmeno s adjektivní deklinací", "P"),
+			val("5", "pronoun on after a preposition", "zájmeno on po předložce", "P"),
+			val("6", "reflexive pronoun se (long forms)", "zvratné zájmeno se (dlouhé tvary)", "P"),
+			val("7", "reflexive pronoun se/si (clitic)", "zvratné zájmeno se/si (klitika)", "P"),
+			val("8", "possessive reflexive pronoun svůj", "přivlastňovací zvratné zájmeno svůj", "P"),
+			val("9", "relative pronoun jenž after a preposition", "vztažné zájmeno jenž po předložce", "P"),
+			val(":", "punctuation", "interpunkce", "Z"),
+			val(";", "abbreviation used as a noun", "zkratka užitá jako substantivum", "N"),
+			val("=", "number written using digits", "číslo zapsané číslicemi", "C"),
+			val("?", "numeral kolik", "číslovka kolik", "C"),
+			val("@", "unrecognized word form", "nerozpoznaný tvar", "X"),
+			val("A", "adjective, general", "adjektivum obecné", "A"),
+			val("B", "verb, present or future form", "sloveso, tvar přítomného nebo budoucího času", "V"),
+			val("C", "adjective, nominal (short) form", "adjektivum, jmenný (krátký) tvar", "A"),
+			val("D", "demonstrative pronoun", "ukazovací zájmeno", "P"),
+			val("E", "relative pronoun což", "vztažné zájmeno což", "P"),
+			val("F", "part of a compound preposition", "součást víceslovné předložky", "R"),
+			val("G", "adjective derived from present transgressive", "adjektivum odvozené od přechodníku přítomného", "A"),
+			val("H", "personal pronoun, clitic form", "osobní zájmeno, klitický tvar", "P"),
+			val("I", "interjection", "citoslovce", "I"),
+			val("J", "relative pronoun jenž", "vztažné zájmeno jenž", "P"),
+			val("K", "relative/interrogative pronoun kdo", "vztažné/tázací zájmeno kdo", "P"),
+			val("L", "indefinite pronoun všechen, sám", "neurčité zájmeno všechen, sám", "P"),
+			val("M", "adjective derived from past transgressive", "adjektivum odvozené od přechodníku minulého", "A"),
+			val("N", "noun, general", "substantivum obecné", "N"),
+			val("O", "pronoun svůj, nesvůj, tentam (standalone)", "zájmeno svůj, nesvůj, tentam (samostatně)", "P"),
+			val("P", "personal pronoun", "osobní zájmeno", "P"),
+			val("Q", "relative/interrogative pronoun co", "vztažné/tázací zájmeno co", "P"),
+			val("R", "preposition, general", "předložka obecná", "R"),
+			val("S", "possessive pronoun", "přivlastňovací zájmeno", "P"),
+			val("T", "particle", "částice", "T"),
+			val("U", "possessive adjective", "přivlastňovací adjektivum", "A"),
+			val("V", "preposition, vocalized", "vokalizovaná předložka", "R"),
+			val("W", "negative pronoun", "záporné zájmeno", "P"),
+			val("X", "word form recognized, tag missing", "rozpoznaný tvar bez značky", "X"),
+			val("Y", "pronoun co as an enclitic", "zájmeno co jako enklitika", "P"),
+			val("Z", "indefinite pronoun", "neurčité zájmeno", "P"),
+			val("^", "coordinating conjunction", "souřadicí spojka", "J"),
+			val("a", "indefinite numeral", "neurčitá číslovka", "C"),
+			val("b", "adverb without grade and negation", "adverbium bez stupňování a negace", "D"),
+			val("c", "conditional (aby, kdyby)", "kondicionál (aby, kdyby)", "V"),
+			val("d", "generic numeral with adjectival declension", "druhová číslovka s adjektivní deklinací", "C"),
+			val("e", "present transgressive", "přechodník přítomný", "V"),
+			val("f", "infinitive", "infinitiv", "V"),
+			val("g", "adverb with grade or negation", "adverbium se stupňováním nebo negací", "D"),
+			val("h", "generic numeral (jedny, oboje)", "druhová číslovka (jedny, oboje)", "C"),
+			val("i", "imperative", "imperativ", "V"),
+			val("j", "generic numeral greater than 3 used as a noun",
+				"druhová číslovka větší než 3 užitá jako substantivum", "C"),
+			val("k", "generic numeral greater than 3 used as an adjective",
+				"druhová číslovka větší než 3 užitá jako adjektivum", "C"),
+			val("l", "cardinal numeral 1-4, půl, sto, tisíc", "základní číslovka 1-4, půl, sto, tisíc", "C"),
+			val("m", "past transgressive", "přechodník minulý", "V"),
+			val("n", "cardinal numeral greater than 4", "základní číslovka větší než 4", "C"),
+			val("o", "indefinite multiplicative numeral", "neurčitá násobná číslovka", "C"),
+			val("p", "past participle", "příčestí minulé", "V"),
+			val("q", "past participle, archaic", "příčestí minulé, archaické", "V"),
+			val("r", "ordinal numeral", "řadová číslovka", "C"),
+			val("s", "passive participle", "příčestí trpné", "V"),
+			val("t", "present or future form, archaic", "tvar přítomného nebo budoucího času, archaický", "V"),
+			val("u", "interrogative numeral kolikrát", "tázací číslovka kolikrát", "C"),
+			val("v", "definite multiplicative numeral", "určitá násobná číslovka", "C"),
+			val("w", "indefinite numeral with adjectival declension", "neurčitá číslovka s adjektivní deklinací", "C"),
+			val("x", "abbreviation, part of speech unknown", "zkratka, neurčený slovní druh", "X"),
+			val("y", "fraction numeral ending with -ina", "zlomková číslovka zakončená -ina", "C"),
+			val("z", "interrogative numeral kolikátý", "tázací číslovka kolikátý", "C"),
+			val("}", "numeral written using Roman numerals", "číslovka zapsaná římskými číslicemi", "C"),
+			val("~", "abbreviation used as a verb", "zkratka užitá jako sloveso", "V"),
+		},
+	}
+}
+
+func cncGender(id string, lbl map[string]string) Position {
+	return Position{
+		ID:     id,
+		Labels: lbl,
+		Values: []Value{
+			notApplicable(),
+			val("F", "feminine", "ženský"),
+			val("H", "feminine or neuter", "ženský nebo střední"),
+			val("I", "masculine inanimate", "mužský neživotný"),
+			val("M", "masculine animate", "mužský životný"),
+			val("N", "neuter", "střední"),
+			val("Q", "feminine (singular) or neuter (plural)", "ženský (jednotné č.) nebo střední (množné č.)"),
+			val("T", "masculine inanimate or feminine (plural)", "mužský neživotný nebo ženský (množné č.)"),
+			anyValue(),
+			val("Y", "masculine (animate or inanimate)", "mužský (životný nebo neživotný)"),
+			val("Z", "not feminine", "jiný než ženský"),
+		},
+	}
+}
+
+func cncNumber(id string, lbl map[string]string) Position {
+	return Position{
+		ID:     id,
+		Labels: lbl,
+		Values: []Value{
+			notApplicable(),
+			val("D", "dual", "dvojné"),
+			val("P", "plural", "množné"),
+			val("S", "singular", "jednotné"),
+			val("W", "singular for feminine, plural for neuter",
+				"jednotné pro ženský rod, množné pro střední rod"),
+			anyValue(),
+		},
+	}
+}
+
+func cncCase() Position {
+	return Position{
+		ID:     "case",
+		Labels: labels("case", "pád"),
+		Values: []Value{
+			notApplicable(),
+			val("1", "nominative", "nominativ"),
+			val("2", "genitive", "genitiv"),
+			val("3", "dative", "dativ"),
+			val("4", "accusative", "akuzativ"),
+			val("5", "vocative", "vokativ"),
+			val("6", "locative", "lokál"),
+			val("7", "instrumental", "instrumentál"),
+			anyValue(),
+		},
+	}
+}
+
+func cncPerson() Position {
+	return Position{
+		ID:     "person",
+		Labels: labels("person", "osoba"),
+		Values: []Value{
+			notApplicable(),
+			val("1", "first", "první"),
+			val("2", "second", "druhá"),
+			val("3", "third", "třetí"),
+			anyValue(),
+		},
+	}
+}
+
+func cncTense() Position {
+	return Position{
+		ID:     "tense",
+		Labels: labels("tense", "čas"),
+		Values: []Value{
+			notApplicable(),
+			val("F", "future", "budoucí"),
+			val("H", "past or present", "minulý nebo přítomný"),
+			val("P", "present", "přítomný"),
+			val("R", "past", "minulý"),
+			anyValue(),
+		},
+	}
+}
+
+func cncGrade() Position {
+	return Position{
+		ID:     "grade",
+		Labels: labels("degree of comparison", "stupeň"),
+		Values: []Value{
+			notApplicable(),
+			val("1", "positive", "pozitiv"),
+			val("2", "comparative", "komparativ"),
+			val("3", "superlative", "superlativ"),
+		},
+	}
+}
+
+func cncNegation() Position {
+	return Position{
+		ID:     "negation",
+		Labels: labels("negation", "negace"),
+		Values: []Value{
+			notApplicable(),
+			val("A", "affirmative", "kladný tvar"),
+			val("N", "negated", "záporný tvar"),
+		},
+	}
+}
+
+func cncVoice() Position {
+	return Position{
+		ID:     "voice",
+		Labels: labels("voice", "slovesný rod"),
+		Values: []Value{
+			notApplicable(),
+			val("A", "active", "činný"),
+			val("P", "passive", "trpný"),
+		},
+	}
+}
+
+func cncReserve(id string) Position {
+	return Position{
+		ID:     id,
+		Labels: labels("unused", "nevyužito"),
+		Values: []Value{notApplicable()},
+	}
+}
+
+func cncVariant() Position {
+	return Position{
+		ID:     "variant",
+		Labels: labels("variant", "varianta"),
+		Values: []Value{
+			val(NotApplicable, "basic form", "základní tvar"),
+			val("1", "variant, less frequent", "varianta, méně častá"),
+			val("2", "variant, rare or archaic", "varianta, řídká nebo zastaralá"),
+			val("3", "very archaic", "velmi zastaralý tvar"),
+			val("4", "very archaic, also archaic", "velmi zastaralý, i zastaralý tvar"),
+			val("5", "colloquial", "hovorový tvar"),
+			val("6", "colloquial, standard in some contexts", "hovorový tvar, v některých kontextech spisovný"),
+			val("7", "colloquial, non-standard", "hovorový tvar, nespisovný"),
+			val("8", "abbreviated form", "zkrácený tvar"),
+			val("9", "special usage", "zvláštní užití"),
+		},
+	}
+}
+
+func cncAspect() Position {
+	return Position{
+		ID:     "aspect",
+		Labels: labels("aspect", "vid"),
+		Values: []Value{
+			notApplicable(),
+			val("P", "perfective", "dokonavý"),
+			val("I", "imperfective", "nedokonavý"),
+			val("B", "both perfective and imperfective", "obouvidový"),
+		},
+	}
+}
+
+func cncCommonPositions() []Position {
+	return []Position{
+		cncPOS(),
+		cncSubPOS(),
+		cncGender("gender", labels("gender", "rod")),
+		cncNumber("number", labels("number", "číslo")),
+		cncCase(),
+		cncGender("possGender", labels("possessor's gender", "rod vlastníka")),
+		cncNumber("possNumber", labels("possessor's number", "číslo vlastníka")),
+		cncPerson(),
+		cncTense(),
+		cncGrade(),
+		cncNegation(),
+		cncVoice(),
+	}
+}
+
+func cnc2000Schema() *Schema {
+	return &Schema{
+		ID:     corp.TagsetCSCNC2000,
+		Labels: labels("CNC positional tagset (2000)", "poziční značky ČNK (2000)"),
+		Positions: append(
+			cncCommonPositions(),
+			cncReserve("reserve1"),
+			cncReserve("reserve2"),
+			cncVariant(),
+		),
+	}
+}
+
+// cnc2000SPKSchema describes the tagset used in spoken corpora. It extends
+// the cs_cnc2000 tags with a 16th position whose values are not enumerated.
+func cnc2000SPKSchema() *Schema {
+	return &Schema{
+		ID:     corp.TagsetCSCNC2000SPK,
+		Labels: labels("CNC positional tagset (2000), spoken", "poziční značky ČNK (2000), mluvené"),
+		Positions: append(
+			cncCommonPositions(),
+			cncReserve("reserve1"),
+			cncReserve("reserve2"),
+			cncVariant(),
+			Position{
+				ID:     "spoken",
+				Labels: labels("spoken language specific", "specifické pro mluvený jazyk"),
+				Values: []Value{notApplicable()},
+				Open:   true,
+			},
+		),
+	}
+}
+
+// cnc2020Schema describes the tagset introduced with SYN2020 where
+// the former reserved positions contain aspect and aggregate markers.
+func cnc2020Schema() *Schema {
+	return &Schema{
+		ID:     corp.TagsetCSCNC2020,
+		Labels: labels("CNC positional tagset (2020)", "poziční značky ČNK (2020)"),
+		Positions: append(
+			cncCommonPositions(),
+			cncAspect(),
+			Position{
+				ID:     "aggregate",
+				Labels: labels("aggregate", "agregát"),
+				Values: []Value{notApplicable()},
+				Open:   true,
+			},
+			cncVariant(),
+		),
+	}
+}
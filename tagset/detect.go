@@ -0,0 +1,241 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/czcorpus/cnc-gokit/collections"
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// openPositionBreadth is a number of values an open position is counted
+// as when comparing how permissive schemas are
+const openPositionBreadth = 100
+
+// PositionStats contains conformance statistics of a single
+// tag position. Only samples with a matching tag length are checked.
+type PositionStats struct {
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+
+	// Valid is the number of samples with a valid value at the position
+	Valid int `json:"valid"`
+
+	// Checked is the number of samples examined at the position
+	Checked int `json:"checked"`
+
+	// Invalid contains frequencies of encountered values not allowed
+	// by the schema
+	Invalid map[string]int `json:"invalid,omitempty"`
+}
+
+// Conformance returns the ratio of valid values at the position
+func (ps PositionStats) Conformance() float64 {
+	if ps.Checked == 0 {
+		return 0
+	}
+	return float64(ps.Valid) / float64(ps.Checked)
+}
+
+// DetectionResult describes how well a sample of values
+// conforms to a tagset
+type DetectionResult struct {
+	Tagset corp.SupportedTagset `json:"tagset"`
+
+	// Score is the ratio of valid samples
+	Score float64 `json:"score"`
+
+	Samples int `json:"samples"`
+
+	Valid int `json:"valid"`
+
+	// LengthMismatch is the number of samples with a different number
+	// of positions than the schema defines (positional tagsets only)
+	LengthMismatch int `json:"lengthMismatch,omitempty"`
+
+	// Positions contains per-position statistics
+	// (positional tagsets only)
+	Positions []PositionStats `json:"positions,omitempty"`
+
+	breadth int
+}
+
+// ------
+
+func schemaBreadth(schema *Schema) int {
+	var ans int
+	for _, p := range schema.Positions {
+		if p.Open {
+			ans += openPositionBreadth
+
+		} else {
+			ans += len(p.Values)
+		}
+	}
+	return ans
+}
+
+func positionValid(p Position, code, pos string) bool {
+	v, ok := p.FindValue(code)
+	if !ok {
+		return false
+	}
+	return len(v.RequiresPOS) == 0 || collections.SliceContains(v.RequiresPOS, pos)
+}
+
+func detectPositional(schema *Schema, samples []string) DetectionResult {
+	ans := DetectionResult{
+		Tagset:    schema.ID,
+		Samples:   len(samples),
+		Positions: make([]PositionStats, schema.Len()),
+		breadth:   schemaBreadth(schema),
+	}
+	for i, p := range schema.Positions {
+		ans.Positions[i] = PositionStats{ID: p.ID, Index: i, Invalid: make(map[string]int)}
+	}
+	for _, tag := range samples {
+		codes := []rune(tag)
+		if len(codes) != schema.Len() {
+			ans.LengthMismatch++
+			continue
+		}
+		valid := true
+		for i, p := range schema.Positions {
+			ans.Positions[i].Checked++
+			if positionValid(p, string(codes[i]), string(codes[0])) {
+				ans.Positions[i].Valid++
+
+			} else {
+				ans.Positions[i].Invalid[string(codes[i])]++
+				valid = false
+			}
+		}
+		if valid {
+			ans.Valid++
+		}
+	}
+	for i := range ans.Positions {
+		if len(ans.Positions[i].Invalid) == 0 {
+			ans.Positions[i].Invalid = nil
+		}
+	}
+	if ans.Samples > 0 {
+		ans.Score = float64(ans.Valid) / float64(ans.Samples)
+	}
+	return ans
+}
+
+// isUDValue tests whether the value is either a UPOS tag
+// or a FEATS value
+func isUDValue(v string) bool {
+	if ValidateUPOS(v) == nil || v == UDEmpty {
+		return true
+	}
+	if !strings.Contains(v, "=") {
+		return false
+	}
+	_, err := ParseFeats(v)
+	return err == nil
+}
+
+func detectUD(samples []string) DetectionResult {
+	ans := DetectionResult{
+		Tagset:  corp.TagsetUD,
+		Samples: len(samples),
+	}
+	for _, v := range samples {
+		if isUDValue(v) {
+			ans.Valid++
+		}
+	}
+	if ans.Samples > 0 {
+		ans.Score = float64(ans.Valid) / float64(ans.Samples)
+	}
+	return ans
+}
+
+// Detect scores all the tagsets known to the registry against a sample
+// of attribute values. Empty values are ignored. Results are sorted
+// from the best match. In case of equal scores, the stricter tagset
+// is preferred (e.g. cs_cnc2000 over cs_cnc2020 if no sample uses
+// the aspect position).
+func (r *Registry) Detect(samples []string) []DetectionResult {
+	filtered := make([]string, 0, len(samples))
+	for _, v := range samples {
+		if v != "" {
+			filtered = append(filtered, v)
+		}
+	}
+	ans := make([]DetectionResult, 0, len(r.schemas)+1)
+	for _, ts := range r.IDs() {
+		if ts == corp.TagsetUD {
+			ans = append(ans, detectUD(filtered))
+
+		} else {
+			ans = append(ans, detectPositional(r.schemas[ts], filtered))
+		}
+	}
+	sort.SliceStable(ans, func(i, j int) bool {
+		if ans[i].Score != ans[j].Score {
+			return ans[i].Score > ans[j].Score
+		}
+		return ans[i].breadth < ans[j].breadth
+	})
+	return ans
+}
+
+// DetectBest returns the best matching tagset. The second returned
+// value is false in case no tagset matches any of the samples.
+func (r *Registry) DetectBest(samples []string) (DetectionResult, bool) {
+	ans := r.Detect(samples)
+	if len(ans) == 0 || ans[0].Score == 0 {
+		return DetectionResult{}, false
+	}
+	return ans[0], true
+}
+
+// Detect scores the built-in tagsets. See Registry.Detect for details.
+func Detect(samples []string) []DetectionResult {
+	return builtin.Detect(samples)
+}
+
+// DetectBest returns the best matching built-in tagset.
+// See Registry.DetectBest for details.
+func DetectBest(samples []string) (DetectionResult, bool) {
+	return builtin.DetectBest(samples)
+}
+
+// SampleAttr collects non-empty values of a positional attribute
+// from tokens of concordance lines. The `limit` specifies the maximum
+// number of values (zero means no limit).
+func SampleAttr(lines []concordance.Line, attr string, limit int) []string {
+	ans := make([]string, 0, len(lines)*5)
+	for _, line := range lines {
+		for _, tok := range line.Text.Tokens() {
+			if v := tok.Attr(attr); v != "" {
+				ans = append(ans, v)
+				if limit > 0 && len(ans) >= limit {
+					return ans
+				}
+			}
+		}
+	}
+	return ans
+}
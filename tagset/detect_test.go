@@ -0,0 +1,104 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"testing"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCNC2000(t *testing.T) {
+	ans, ok := DetectBest([]string{"NNFS2-----A----", "VB-S---3P-AA---", "Z:-------------", ""})
+	assert.True(t, ok)
+	assert.Equal(t, corp.TagsetCSCNC2000, ans.Tagset)
+	assert.Equal(t, 3, ans.Samples)
+	assert.Equal(t, 1.0, ans.Score)
+
+	all := Detect([]string{"NNFS2-----A----"})
+	assert.Equal(t, corp.TagsetCSCNC2000, all[0].Tagset)
+	assert.Equal(t, corp.TagsetCSCNC2020, all[1].Tagset)
+	assert.Equal(t, 1.0, all[1].Score)
+}
+
+func TestDetectCNC2020PositionStats(t *testing.T) {
+	ans := Detect([]string{"VB-S---3P-AAI--", "VB-S---3P-AAP--", "NNFS2-----A----"})
+	assert.Equal(t, corp.TagsetCSCNC2020, ans[0].Tagset)
+	assert.Equal(t, 1.0, ans[0].Score)
+	for _, v := range ans {
+		if v.Tagset == corp.TagsetCSCNC2000 {
+			assert.InDelta(t, 1.0/3.0, v.Score, 0.0001)
+			reserve := v.Positions[12]
+			assert.Equal(t, "reserve1", reserve.ID)
+			assert.Equal(t, 3, reserve.Checked)
+			assert.Equal(t, 1, reserve.Valid)
+			assert.Equal(t, map[string]int{"I": 1, "P": 1}, reserve.Invalid)
+			assert.Nil(t, v.Positions[0].Invalid)
+		}
+		if v.Tagset == corp.TagsetCSCNC2000SPK {
+			assert.Equal(t, 3, v.LengthMismatch)
+			assert.Equal(t, 0.0, v.Score)
+		}
+	}
+}
+
+func TestDetectUD(t *testing.T) {
+	ans, ok := DetectBest([]string{"NOUN", "Case=Nom|Gender=Fem|Number=Sing", "_", "foo"})
+	assert.True(t, ok)
+	assert.Equal(t, corp.TagsetUD, ans.Tagset)
+	assert.Equal(t, 0.75, ans.Score)
+	assert.Nil(t, ans.Positions)
+}
+
+func TestDetectNoMatch(t *testing.T) {
+	_, ok := DetectBest([]string{"foo", "bar"})
+	assert.False(t, ok)
+	_, ok = DetectBest([]string{})
+	assert.False(t, ok)
+}
+
+func TestDetectRegistryTagset(t *testing.T) {
+	r := NewRegistry()
+	assert.NoError(t, r.Add(&Schema{
+		ID: "xx_test",
+		Positions: []Position{
+			{ID: "pos", Values: []Value{{Code: "N"}, {Code: "V"}}},
+			{ID: "num", Values: []Value{{Code: "S"}, {Code: "P"}}},
+		},
+	}))
+	ans, ok := r.DetectBest([]string{"NS", "VP", "NP"})
+	assert.True(t, ok)
+	assert.Equal(t, corp.SupportedTagset("xx_test"), ans.Tagset)
+}
+
+func TestSampleAttr(t *testing.T) {
+	lines := []concordance.Line{
+		{Text: concordance.TokenSlice{
+			&concordance.Token{Word: "a", Attrs: map[string]string{"tag": "X"}},
+			&concordance.Struct{Name: "g"},
+			&concordance.Token{Word: "b", Attrs: map[string]string{"tag": ""}},
+			&concordance.Token{Word: "c", Attrs: map[string]string{"tag": "Y"}},
+		}},
+		{Text: concordance.TokenSlice{
+			&concordance.Token{Word: "d", Attrs: map[string]string{"tag": "Z"}},
+		}},
+	}
+	assert.Equal(t, []string{"X", "Y", "Z"}, SampleAttr(lines, "tag", 0))
+	assert.Equal(t, []string{"X", "Y"}, SampleAttr(lines, "tag", 2))
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/czcorpus/cnc-gokit/collections"
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// EnrichOptions configures token enrichment
+type EnrichOptions struct {
+
+	// Tagset specifies the tagset to decode. If empty, the first
+	// tagset of the corpus is used.
+	Tagset corp.SupportedTagset
+
+	// TagAttr is an attribute containing positional tags or,
+	// in case of UD, FEATS values. If empty, "tag" is used
+	// for positional tagsets and "feats" for UD.
+	TagAttr string
+
+	// PosAttr is an attribute containing UPOS values (UD only, optional).
+	PosAttr string
+
+	// Lang is a language of labels ("en" is used as a fallback)
+	Lang string
+}
+
+// maxEnricherCacheSize limits the number of cached decoded tags.
+// Once reached, the cache is cleared.
+const maxEnricherCacheSize = 10000
+
+// Enricher attaches decoded morphological features to concordance
+// tokens (see concordance.Token.Features). It is safe for concurrent
+// use so a single instance can be shared e.g. by request handlers.
+type Enricher struct {
+	tagset  corp.SupportedTagset
+	schema  *Schema
+	tagAttr string
+	posAttr string
+	lang    string
+
+	// cache contains already decoded tags
+	cache     map[string]map[string]concordance.TokenFeature
+	cacheLock sync.Mutex
+}
+
+// NewEnricher creates an enricher for a corpus. The tagset must be listed
+// in the corpus tagsets and known to the registry. In case the corpus
+// specifies its positional attributes, the configured attributes must
+// be among them.
+func (r *Registry) NewEnricher(setup *corp.CorpusSetup, opts EnrichOptions) (*Enricher, error) {
+	ts := opts.Tagset
+	if ts == "" {
+		if len(setup.Tagsets) == 0 {
+			return nil, fmt.Errorf("cannot enrich tokens of %s: no tagset configured", setup.ID)
+		}
+		ts = setup.Tagsets[0]
+
+	} else if !collections.SliceContains(setup.Tagsets, ts) {
+		return nil, fmt.Errorf("cannot enrich tokens of %s: tagset %s not configured for the corpus", setup.ID, ts)
+	}
+	ans := &Enricher{
+		tagset:  ts,
+		tagAttr: opts.TagAttr,
+		posAttr: opts.PosAttr,
+		lang:    opts.Lang,
+		cache:   make(map[string]map[string]concordance.TokenFeature),
+	}
+	if ts == corp.TagsetUD {
+		if ans.tagAttr == "" {
+			ans.tagAttr = "feats"
+		}
+
+	} else {
+		schema, ok := r.Get(ts)
+		if !ok {
+			return nil, fmt.Errorf("cannot enrich tokens of %s: unknown tagset %s", setup.ID, ts)
+		}
+		ans.schema = schema
+		if ans.tagAttr == "" {
+			ans.tagAttr = defaultTagAttr
+		}
+		ans.posAttr = ""
+	}
+	for _, attr := range []string{ans.tagAttr, ans.posAttr} {
+		if attr != "" && len(setup.PosAttrs) > 0 && !setup.PosAttrs.Contains(attr) {
+			return nil, fmt.Errorf("cannot enrich tokens of %s: unknown attribute %s", setup.ID, attr)
+		}
+	}
+	return ans, nil
+}
+
+// NewEnricher creates an enricher for one of the built-in tagsets.
+// See Registry.NewEnricher for details.
+func NewEnricher(setup *corp.CorpusSetup, opts EnrichOptions) (*Enricher, error) {
+	return builtin.NewEnricher(setup, opts)
+}
+
+func (e *Enricher) decodePositional(tag string) map[string]concordance.TokenFeature {
+	feats, err := e.schema.Decode(tag, e.lang)
+	if err != nil {
+		return nil
+	}
+	ans := make(map[string]concordance.TokenFeature, len(feats))
+	for _, f := range feats {
+		ans[f.ID] = concordance.TokenFeature{
+			Label:      f.Label,
+			Value:      f.Code,
+			ValueLabel: f.ValueLabel,
+		}
+	}
+	return ans
+}
+
+func (e *Enricher) decodeUD(feats string) map[string]concordance.TokenFeature {
+	parsed, err := ParseFeats(feats)
+	if err != nil {
+		return nil
+	}
+	ans := make(map[string]concordance.TokenFeature, len(parsed)+1)
+	for _, name := range parsed.Names() {
+		value := parsed.Get(name)
+		ans[name] = concordance.TokenFeature{
+			Label:      UDFeatureLabel(name, e.lang),
+			Value:      value,
+			ValueLabel: UDValueLabel(name, value, e.lang),
+		}
+	}
+	return ans
+}
+
+// EnrichToken decodes the token's tag and sets its Features. In case
+// the tag is missing or invalid, Features are left empty and false
+// is returned. For UD, an empty FEATS value (`_`) is valid and produces
+// no features (or just the UDPosFeature one in case UPOS is available).
+func (e *Enricher) EnrichToken(tok *concordance.Token) bool {
+	tag, ok := tok.Attrs[e.tagAttr]
+	if !ok {
+		tok.Features = nil
+		return false
+	}
+	var upos string
+	if e.posAttr != "" {
+		upos = tok.Attr(e.posAttr)
+	}
+	key := tag
+	if upos != "" {
+		key = upos + "\t" + tag
+	}
+	e.cacheLock.Lock()
+	feats, ok := e.cache[key]
+	e.cacheLock.Unlock()
+	if !ok {
+		if e.schema != nil {
+			if tag != "" {
+				feats = e.decodePositional(tag)
+			}
+
+		} else {
+			feats = e.decodeUD(tag)
+			if feats != nil && upos != "" {
+				if ValidateUPOS(upos) == nil {
+					feats[UDPosFeature] = concordance.TokenFeature{
+						Label:      UDFeatureLabel(UDPosFeature, e.lang),
+						Value:      upos,
+						ValueLabel: UDValueLabel(UDPosFeature, upos, e.lang),
+					}
+
+				} else {
+					feats = nil
+				}
+			}
+		}
+		e.cacheLock.Lock()
+		if len(e.cache) >= maxEnricherCacheSize {
+			clear(e.cache)
+		}
+		e.cache[key] = feats
+		e.cacheLock.Unlock()
+	}
+	// each token gets its own copy so the cached value cannot be
+	// changed via the token
+	tok.Features = maps.Clone(feats)
+	return feats != nil
+}
+
+// Enrich sets Features of all the tokens of the lines (including
+// aligned texts). It returns the number of tokens which could not
+// be decoded.
+func (e *Enricher) Enrich(lines []concordance.Line) int {
+	var failed int
+	for _, line := range lines {
+		for _, text := range []concordance.TokenSlice{line.Text, line.AlignedText} {
+			for _, tok := range text.Tokens() {
+				if !e.EnrichToken(tok) {
+					failed++
+				}
+			}
+		}
+	}
+	return failed
+}
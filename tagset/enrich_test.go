@@ -0,0 +1,188 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/czcorpus/mquery-common/concordance"
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrichPositional(t *testing.T) {
+	setup := &corp.CorpusSetup{
+		ID:       "syn2020",
+		Tagsets:  []corp.SupportedTagset{corp.TagsetCSCNC2020},
+		PosAttrs: corp.PosAttrList{{Name: "word"}, {Name: "tag"}},
+	}
+	e, err := NewEnricher(setup, EnrichOptions{Lang: "cs"})
+	assert.NoError(t, err)
+	lines := []concordance.Line{
+		{Text: concordance.TokenSlice{
+			&concordance.Token{Word: "ženy", Attrs: map[string]string{"tag": "NNFS2-----A----"}},
+			&concordance.Struct{Name: "g"},
+			&concordance.Token{Word: "xy", Attrs: map[string]string{"tag": "???"}},
+		}},
+	}
+	assert.Equal(t, 1, e.Enrich(lines))
+	tok := lines[0].Text.Tokens()[0]
+	assert.Equal(
+		t,
+		concordance.TokenFeature{Label: "pád", Value: "2", ValueLabel: "genitiv"},
+		tok.Features["case"],
+	)
+	_, ok := tok.Features["variant"]
+	assert.False(t, ok)
+	assert.Nil(t, lines[0].Text.Tokens()[1].Features)
+
+	data, err := json.Marshal(tok)
+	assert.NoError(t, err)
+	var tok2 concordance.Token
+	assert.NoError(t, json.Unmarshal(data, &tok2))
+	assert.Equal(t, tok.Features, tok2.Features)
+
+	data, err = json.Marshal(lines[0].Text.Tokens()[1])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "features")
+}
+
+func TestEnrichUD(t *testing.T) {
+	setup := &corp.CorpusSetup{
+		ID:      "ud_test",
+		Tagsets: []corp.SupportedTagset{corp.TagsetCSCNC2000, corp.TagsetUD},
+	}
+	e, err := NewEnricher(setup, EnrichOptions{Tagset: corp.TagsetUD, PosAttr: "upos"})
+	assert.NoError(t, err)
+	tok := &concordance.Token{
+		Word:  "ženy",
+		Attrs: map[string]string{"upos": "NOUN", "feats": "Case=Gen|Number=Sing"},
+	}
+	assert.True(t, e.EnrichToken(tok))
+	assert.Equal(t, "Gen", tok.Features["Case"].Value)
+	assert.Equal(t, "NOUN", tok.Features[UDPosFeature].Value)
+
+	tok = &concordance.Token{Word: "x", Attrs: map[string]string{"upos": "FOO", "feats": "_"}}
+	assert.False(t, e.EnrichToken(tok))
+
+	tok = &concordance.Token{Word: "a", Attrs: map[string]string{"upos": "CCONJ", "feats": "_"}}
+	assert.True(t, e.EnrichToken(tok))
+	assert.Equal(
+		t,
+		map[string]concordance.TokenFeature{
+			UDPosFeature: {Label: "part of speech", Value: "CCONJ", ValueLabel: "coordinating conjunction"},
+		},
+		tok.Features,
+	)
+
+	tok = &concordance.Token{Word: "x", Attrs: map[string]string{"upos": "NOUN"}}
+	assert.False(t, e.EnrichToken(tok))
+	assert.Nil(t, tok.Features)
+}
+
+func TestEnrichUDNoFeats(t *testing.T) {
+	setup := &corp.CorpusSetup{ID: "ud_test", Tagsets: []corp.SupportedTagset{corp.TagsetUD}}
+	e, err := NewEnricher(setup, EnrichOptions{Lang: "cs"})
+	assert.NoError(t, err)
+	lines := []concordance.Line{
+		{
+			Text: concordance.TokenSlice{
+				&concordance.Token{Word: ",", Attrs: map[string]string{"feats": "_"}},
+				&concordance.Token{Word: "ženy", Attrs: map[string]string{"feats": "Case=Gen|Gender=Fem,Neut"}},
+			},
+			AlignedText: concordance.TokenSlice{
+				&concordance.Token{Word: "women", Attrs: map[string]string{"feats": "Number=Plur"}},
+				&concordance.Token{Word: "x", Attrs: map[string]string{}},
+			},
+		},
+	}
+	assert.Equal(t, 1, e.Enrich(lines))
+	assert.Empty(t, lines[0].Text.Tokens()[0].Features)
+	assert.Equal(
+		t,
+		concordance.TokenFeature{Label: "pád", Value: "Gen", ValueLabel: "genitiv"},
+		lines[0].Text.Tokens()[1].Features["Case"],
+	)
+	assert.Equal(t, "ženský, střední", lines[0].Text.Tokens()[1].Features["Gender"].ValueLabel)
+	assert.Equal(
+		t,
+		concordance.TokenFeature{Label: "číslo", Value: "Plur", ValueLabel: "plurál"},
+		lines[0].AlignedText.Tokens()[0].Features["Number"],
+	)
+	assert.Nil(t, lines[0].AlignedText.Tokens()[1].Features)
+}
+
+func TestEnrichFeaturesNotShared(t *testing.T) {
+	setup := &corp.CorpusSetup{ID: "syn2020", Tagsets: []corp.SupportedTagset{corp.TagsetCSCNC2020}}
+	e, err := NewEnricher(setup, EnrichOptions{})
+	assert.NoError(t, err)
+	tok1 := &concordance.Token{Word: "ženy", Attrs: map[string]string{"tag": "NNFS2-----A----"}}
+	tok2 := &concordance.Token{Word: "matky", Attrs: map[string]string{"tag": "NNFS2-----A----"}}
+	assert.True(t, e.EnrichToken(tok1))
+	delete(tok1.Features, "case")
+	assert.True(t, e.EnrichToken(tok2))
+	assert.Equal(t, "2", tok2.Features["case"].Value)
+}
+
+func TestNewEnricherErrors(t *testing.T) {
+	_, err := NewEnricher(&corp.CorpusSetup{ID: "foo"}, EnrichOptions{})
+	assert.Error(t, err)
+	setup := &corp.CorpusSetup{
+		ID:       "foo",
+		Tagsets:  []corp.SupportedTagset{corp.TagsetCSCNC2000},
+		PosAttrs: corp.PosAttrList{{Name: "word"}, {Name: "tag"}},
+	}
+	_, err = NewEnricher(setup, EnrichOptions{Tagset: corp.TagsetCSCNC2020})
+	assert.Error(t, err)
+	_, err = NewEnricher(setup, EnrichOptions{TagAttr: "ptag"})
+	assert.Error(t, err)
+	setup.Tagsets = []corp.SupportedTagset{"sk_snk"}
+	_, err = NewEnricher(setup, EnrichOptions{})
+	assert.Error(t, err)
+}
+
+func TestEnrichConcurrent(t *testing.T) {
+	setup := &corp.CorpusSetup{ID: "ud_test", Tagsets: []corp.SupportedTagset{corp.TagsetUD}}
+	e, err := NewEnricher(setup, EnrichOptions{})
+	assert.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				tok := &concordance.Token{Attrs: map[string]string{"feats": fmt.Sprintf("Person=%d", (i*j)%4)}}
+				assert.True(t, e.EnrichToken(tok))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestEnrichCacheLimit(t *testing.T) {
+	setup := &corp.CorpusSetup{ID: "ud_test", Tagsets: []corp.SupportedTagset{corp.TagsetUD}}
+	e, err := NewEnricher(setup, EnrichOptions{})
+	assert.NoError(t, err)
+	for i := 0; i < maxEnricherCacheSize+10; i++ {
+		tok := &concordance.Token{Attrs: map[string]string{"feats": fmt.Sprintf("Foo=V%d", i)}}
+		assert.True(t, e.EnrichToken(tok))
+	}
+	assert.LessOrEqual(t, len(e.cache), maxEnricherCacheSize)
+}
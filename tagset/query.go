@@ -0,0 +1,225 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+const (
+	// UDPosFeature is a pseudo-feature used in selections
+	// to constrain UD part of speech (UPOS)
+	UDPosFeature = "upos"
+
+	defaultTagAttr = "tag"
+)
+
+// Selection contains allowed values of individual features.
+// For positional tagsets, keys are position IDs (e.g. "pos", "case")
+// and values are position codes (e.g. "N", "2"). For UD, keys are feature
+// names (e.g. "Case") or UDPosFeature and values are feature values
+// (e.g. "Gen") or UPOS tags.
+type Selection map[string][]string
+
+// AttrRegexp is a regular expression constraining a positional attribute
+type AttrRegexp struct {
+	Attr   string `json:"attr"`
+	Regexp string `json:"regexp"`
+}
+
+func (ar AttrRegexp) String() string {
+	return fmt.Sprintf(`%s="%s"`, ar.Attr, strings.ReplaceAll(ar.Regexp, `"`, `\"`))
+}
+
+// TagQuery is a conjunction of attribute constraints
+type TagQuery []AttrRegexp
+
+// String renders the query as a CQL attribute expression
+// (e.g. `tag="N.F.2.*"`) to be used within a token query (`[...]`).
+// An empty query produces an empty string.
+func (tq TagQuery) String() string {
+	items := make([]string, len(tq))
+	for i, v := range tq {
+		items[i] = v.String()
+	}
+	return strings.Join(items, " & ")
+}
+
+// ------
+
+func regexpAlternatives(values []string) string {
+	if len(values) == 1 {
+		return regexp.QuoteMeta(values[0])
+	}
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = regexp.QuoteMeta(v)
+	}
+	sort.Strings(items)
+	return "(" + strings.Join(items, "|") + ")"
+}
+
+func regexpCharClass(codes []string) string {
+	if len(codes) == 1 {
+		return regexp.QuoteMeta(codes[0])
+	}
+	sorted := append([]string{}, codes...)
+	sort.Strings(sorted)
+	var ans strings.Builder
+	ans.WriteString("[")
+	for _, c := range sorted {
+		if c == `\` || c == "]" || c == "^" || c == "-" || c == "[" {
+			ans.WriteString(`\`)
+		}
+		ans.WriteString(c)
+	}
+	ans.WriteString("]")
+	return ans.String()
+}
+
+// PositionalRegexp creates a regular expression matching tags
+// of the schema with the selected values.
+func PositionalRegexp(schema *Schema, sel Selection) (string, error) {
+	parts := make([]string, schema.Len())
+	for i := range parts {
+		parts[i] = "."
+	}
+	for id, codes := range sel {
+		idx := schema.PositionIndex(id)
+		if idx == -1 {
+			return "", fmt.Errorf("unknown position %s in tagset %s", id, schema.ID)
+		}
+		if len(codes) == 0 {
+			continue
+		}
+		for _, c := range codes {
+			if _, ok := schema.Positions[idx].FindValue(c); !ok || len([]rune(c)) != 1 {
+				return "", fmt.Errorf("invalid value `%s` of position %s in tagset %s", c, id, schema.ID)
+			}
+		}
+		parts[idx] = regexpCharClass(codes)
+	}
+	last := len(parts) - 1
+	for last >= 0 && parts[last] == "." {
+		last--
+	}
+	if last == -1 {
+		return ".*", nil
+	}
+	ans := strings.Join(parts[:last+1], "")
+	if last < len(parts)-1 {
+		ans += ".*"
+	}
+	return ans, nil
+}
+
+// UDFeatsRegexp creates a regular expression matching FEATS values
+// (in the canonical order) containing the selected features.
+// For a feature with multiple selected values, any of them is accepted.
+func UDFeatsRegexp(sel Selection) (string, error) {
+	feats := make(Feats)
+	for name, values := range sel {
+		if name == UDPosFeature || len(values) == 0 {
+			continue
+		}
+		if !udFeatNameRegexp.MatchString(name) {
+			return "", fmt.Errorf("invalid UD feature name `%s`", name)
+		}
+		for _, v := range values {
+			if !udFeatValueRegexp.MatchString(v) {
+				return "", fmt.Errorf("invalid value `%s` of UD feature `%s`", v, name)
+			}
+		}
+		feats[name] = values
+	}
+	if len(feats) == 0 {
+		return ".*", nil
+	}
+	items := make([]string, 0, len(feats))
+	for _, name := range feats.Names() {
+		items = append(
+			items,
+			fmt.Sprintf(
+				`%s=([^|]*,)?%s(,[^|]*)?`,
+				regexp.QuoteMeta(name), regexpAlternatives(feats[name])),
+		)
+	}
+	return `(.*\|)?` + strings.Join(items, `\|(.*\|)?`) + `(\|.*)?`, nil
+}
+
+// BuildTagQuery creates a CQL query matching tokens with the selected
+// morphological features. For positional tagsets, the whole tag is expected
+// in the conf.FeatAttr attribute (with conf.PosAttr and "tag" as fallbacks).
+// For UD, UPOS is queried in the conf.PosAttr attribute and the features in
+// the conf.FeatAttr attribute.
+func (r *Registry) BuildTagQuery(ts corp.SupportedTagset, conf corp.Tagset, sel Selection) (TagQuery, error) {
+	if ts == corp.TagsetUD {
+		ans := make(TagQuery, 0, 2)
+		if upos := sel[UDPosFeature]; len(upos) > 0 {
+			if conf.PosAttr == "" {
+				return nil, fmt.Errorf("cannot query UPOS: tagset %s has no posAttr configured", conf.ID)
+			}
+			for _, v := range upos {
+				if err := ValidateUPOS(v); err != nil {
+					return nil, err
+				}
+			}
+			ans = append(ans, AttrRegexp{Attr: conf.PosAttr, Regexp: regexpAlternatives(upos)})
+		}
+		rx, err := UDFeatsRegexp(sel)
+		if err != nil {
+			return nil, err
+		}
+		if rx != ".*" {
+			if conf.FeatAttr == "" {
+				return nil, fmt.Errorf("cannot query features: tagset %s has no featAttr configured", conf.ID)
+			}
+			ans = append(ans, AttrRegexp{Attr: conf.FeatAttr, Regexp: rx})
+		}
+		return ans, nil
+	}
+	schema, ok := r.Get(ts)
+	if !ok {
+		return nil, fmt.Errorf("cannot build tag query: unsupported tagset %s", ts)
+	}
+	rx, err := PositionalRegexp(schema, sel)
+	if err != nil {
+		return nil, err
+	}
+	if rx == ".*" {
+		return TagQuery{}, nil
+	}
+	attr := conf.FeatAttr
+	if attr == "" {
+		attr = conf.PosAttr
+	}
+	if attr == "" {
+		attr = defaultTagAttr
+	}
+	return TagQuery{{Attr: attr, Regexp: rx}}, nil
+}
+
+// BuildTagQuery creates a CQL query for one of the built-in tagsets.
+// See Registry.BuildTagQuery for details.
+func BuildTagQuery(ts corp.SupportedTagset, conf corp.Tagset, sel Selection) (TagQuery, error) {
+	return builtin.BuildTagQuery(ts, conf, sel)
+}
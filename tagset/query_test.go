@@ -0,0 +1,108 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionalQuery(t *testing.T) {
+	q, err := BuildTagQuery(
+		corp.TagsetCSCNC2000,
+		corp.Tagset{FeatAttr: "tag"},
+		Selection{"pos": {"N"}, "gender": {"F"}, "case": {"2"}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, `tag="N.F.2.*"`, q.String())
+}
+
+func TestPositionalQueryMultipleValuesAndEscaping(t *testing.T) {
+	q, err := BuildTagQuery(
+		corp.TagsetCSCNC2000,
+		corp.Tagset{PosAttr: "ptag"},
+		Selection{"subpos": {"^", ","}, "variant": {"5"}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, `ptag=".[,\^]............5"`, q.String())
+	rx := regexp.MustCompile("^(?:" + q[0].Regexp + ")$")
+	assert.True(t, rx.MatchString("J^------------5"))
+	assert.True(t, rx.MatchString("J,------------5"))
+	assert.False(t, rx.MatchString("J^-------------"))
+}
+
+func TestPositionalQueryDefaultAttrAndEmpty(t *testing.T) {
+	q, err := BuildTagQuery(corp.TagsetCSCNC2020, corp.Tagset{}, Selection{"aspect": {"P"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `tag="............P.*"`, q.String())
+
+	q, err = BuildTagQuery(corp.TagsetCSCNC2020, corp.Tagset{}, Selection{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", q.String())
+}
+
+func TestPositionalQueryErrors(t *testing.T) {
+	_, err := BuildTagQuery(corp.TagsetCSCNC2000, corp.Tagset{}, Selection{"foo": {"N"}})
+	assert.Error(t, err)
+	_, err = BuildTagQuery(corp.TagsetCSCNC2000, corp.Tagset{}, Selection{"case": {"8"}})
+	assert.Error(t, err)
+	_, err = BuildTagQuery("foo", corp.Tagset{}, Selection{"case": {"1"}})
+	assert.Error(t, err)
+}
+
+func TestUDQuery(t *testing.T) {
+	q, err := BuildTagQuery(
+		corp.TagsetUD,
+		corp.Tagset{ID: "ud", PosAttr: "upos", FeatAttr: "feats"},
+		Selection{UDPosFeature: {"NOUN"}, "Gender": {"Fem"}, "Case": {"Gen"}},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, q, 2)
+	assert.Equal(t, AttrRegexp{Attr: "upos", Regexp: "NOUN"}, q[0])
+	assert.Equal(t, "feats", q[1].Attr)
+
+	rx := regexp.MustCompile("^(?:" + q[1].Regexp + ")$")
+	assert.True(t, rx.MatchString("Case=Gen|Gender=Fem|Number=Sing|Polarity=Pos"))
+	assert.True(t, rx.MatchString("Animacy=Inan|Case=Gen|Gender=Fem,Neut"))
+	assert.False(t, rx.MatchString("Case=Gen|Gender=Masc|Number=Sing"))
+	assert.False(t, rx.MatchString("Case=Nom|Gender=Fem"))
+	assert.False(t, rx.MatchString("XCase=Gen|Gender=Fem"))
+}
+
+func TestUDQueryAlternatives(t *testing.T) {
+	q, err := BuildTagQuery(
+		corp.TagsetUD,
+		corp.Tagset{PosAttr: "upos", FeatAttr: "feats"},
+		Selection{UDPosFeature: {"PROPN", "NOUN"}, "Number": {"Sing", "Dual"}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, `upos="(NOUN|PROPN)" & feats="(.*\|)?Number=([^|]*,)?(Dual|Sing)(,[^|]*)?(\|.*)?"`, q.String())
+}
+
+func TestUDQueryErrors(t *testing.T) {
+	_, err := BuildTagQuery(corp.TagsetUD, corp.Tagset{FeatAttr: "feats"}, Selection{UDPosFeature: {"NOUN"}})
+	assert.Error(t, err)
+	_, err = BuildTagQuery(corp.TagsetUD, corp.Tagset{PosAttr: "upos"}, Selection{"Case": {"Gen"}})
+	assert.Error(t, err)
+	_, err = BuildTagQuery(corp.TagsetUD, corp.Tagset{PosAttr: "upos"}, Selection{UDPosFeature: {"N"}})
+	assert.Error(t, err)
+	_, err = BuildTagQuery(corp.TagsetUD, corp.Tagset{FeatAttr: "feats"}, Selection{"Case": {"gen"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"gopkg.in/yaml.v3"
+)
+
+// builtin is a registry with the built-in tagsets only
+var builtin = NewRegistry()
+
+// Get returns a built-in schema of a positional tagset. The second
+// returned value is false for unknown or non-positional tagsets.
+func Get(ts corp.SupportedTagset) (*Schema, bool) {
+	return builtin.Get(ts)
+}
+
+// Registry contains known tagsets. Besides the built-in ones,
+// positional tagsets can be added at runtime - typically by loading
+// their definitions from JSON or YAML files.
+type Registry struct {
+	schemas map[corp.SupportedTagset]*Schema
+}
+
+// Get returns a schema of a positional tagset. The second
+// returned value is false for unknown or non-positional tagsets.
+func (r *Registry) Get(ts corp.SupportedTagset) (*Schema, bool) {
+	ans, ok := r.schemas[ts]
+	return ans, ok
+}
+
+// IDs returns sorted identifiers of all the known tagsets
+// (including the non-positional UD tagset).
+func (r *Registry) IDs() []corp.SupportedTagset {
+	ans := make([]corp.SupportedTagset, 0, len(r.schemas)+1)
+	ans = append(ans, corp.TagsetUD)
+	for k := range r.schemas {
+		ans = append(ans, k)
+	}
+	sort.Slice(ans, func(i, j int) bool { return ans[i] < ans[j] })
+	return ans
+}
+
+// Validate tests whether the tagset is known to the registry.
+// As in corp.SupportedTagset.Validate, the empty value is considered OK.
+func (r *Registry) Validate(ts corp.SupportedTagset) error {
+	if ts == "" || ts == corp.TagsetUD {
+		return nil
+	}
+	if _, ok := r.schemas[ts]; ok {
+		return nil
+	}
+	return fmt.Errorf("invalid tagset type: %s", ts)
+}
+
+// ValidateSetup tests whether all the tagsets of the corpus are known
+// to the registry.
+func (r *Registry) ValidateSetup(setup *corp.CorpusSetup) error {
+	errs := make([]error, 0, len(setup.Tagsets))
+	for _, ts := range setup.Tagsets {
+		if err := r.Validate(ts); err != nil {
+			errs = append(errs, fmt.Errorf("corpus %s: %w", setup.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Add adds a positional tagset to the registry. An existing tagset
+// with the same ID is replaced.
+func (r *Registry) Add(schema *Schema) error {
+	if err := validateSchema(schema); err != nil {
+		return err
+	}
+	r.schemas[schema.ID] = schema
+	return nil
+}
+
+func decodeSchema(data []byte, isYAML bool) (*Schema, error) {
+	var ans Schema
+	if isYAML {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&ans); err != nil && err != io.EOF {
+			return nil, err
+		}
+
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&ans); err != nil {
+			return nil, err
+		}
+	}
+	return &ans, nil
+}
+
+// LoadFile loads a single tagset definition from a JSON or YAML file
+// (detected by the ".json", ".yaml" and ".yml" suffixes) and adds it to
+// the registry. Unknown fields are rejected.
+func (r *Registry) LoadFile(path string) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("failed to load tagset from %s: unsupported file type", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load tagset from %s: %w", path, err)
+	}
+	schema, err := decodeSchema(data, ext != ".json")
+	if err != nil {
+		return fmt.Errorf("failed to load tagset from %s: %w", path, err)
+	}
+	if err := r.Add(schema); err != nil {
+		return fmt.Errorf("failed to load tagset from %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadDir loads all the JSON and YAML tagset definitions
+// from a directory (non-recursively). Files are processed in
+// alphabetical order.
+func (r *Registry) LoadDir(path string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fmt.Errorf("failed to load tagsets from %s: %w", path, err)
+	}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		if err := r.LoadFile(filepath.Join(path, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSchema(schema *Schema) error {
+	if schema.ID == "" {
+		return fmt.Errorf("invalid tagset definition: missing id")
+	}
+	if schema.ID == corp.TagsetUD {
+		return fmt.Errorf("invalid tagset definition: %s is reserved for Universal Dependencies", schema.ID)
+	}
+	if len(schema.Positions) == 0 {
+		return fmt.Errorf("invalid tagset definition %s: no positions defined", schema.ID)
+	}
+	posIDs := make(map[string]bool)
+	for i, p := range schema.Positions {
+		if p.ID == "" {
+			return fmt.Errorf("invalid tagset definition %s: position %d has no id", schema.ID, i+1)
+		}
+		if posIDs[p.ID] {
+			return fmt.Errorf("invalid tagset definition %s: duplicate position %s", schema.ID, p.ID)
+		}
+		posIDs[p.ID] = true
+		if len(p.Values) == 0 && !p.Open {
+			return fmt.Errorf("invalid tagset definition %s: position %s has no values", schema.ID, p.ID)
+		}
+		codes := make(map[string]bool)
+		for _, v := range p.Values {
+			if len([]rune(v.Code)) != 1 {
+				return fmt.Errorf(
+					"invalid tagset definition %s: value `%s` of position %s is not a single character",
+					schema.ID, v.Code, p.ID)
+			}
+			if codes[v.Code] {
+				return fmt.Errorf(
+					"invalid tagset definition %s: duplicate value `%s` of position %s",
+					schema.ID, v.Code, p.ID)
+			}
+			codes[v.Code] = true
+			for _, pos := range v.RequiresPOS {
+				if _, ok := schema.Positions[0].FindValue(pos); !ok {
+					return fmt.Errorf(
+						"invalid tagset definition %s: value `%s` of position %s requires unknown part of speech `%s`",
+						schema.ID, v.Code, p.ID, pos)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// NewRegistry creates a registry containing the built-in tagsets
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas: map[corp.SupportedTagset]*Schema{
+			corp.TagsetCSCNC2000:    cnc2000Schema(),
+			corp.TagsetCSCNC2000SPK: cnc2000SPKSchema(),
+			corp.TagsetCSCNC2020:    cnc2020Schema(),
+		},
+	}
+}
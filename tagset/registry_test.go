@@ -0,0 +1,186 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testYAMLTagset = `
+id: sk_snk
+labels:
+  en: Slovak National Corpus
+positions:
+  - id: pos
+    labels:
+      en: part of speech
+    values:
+      - code: S
+        labels:
+          en: noun
+      - code: A
+        labels:
+          en: adjective
+  - id: case
+    labels:
+      en: case
+    values:
+      - code: "1"
+        labels:
+          en: nominative
+        requiresPos: [S, A]
+      - code: "-"
+        labels:
+          en: not applicable
+`
+
+const testJSONTagset = `{
+  "id": "xx_test",
+  "labels": {"en": "Test"},
+  "positions": [
+    {"id": "pos", "labels": {"en": "part of speech"}, "values": [{"code": "N", "labels": {"en": "noun"}}]},
+    {"id": "misc", "labels": {"en": "misc"}, "values": [], "open": true}
+  ]
+}`
+
+func writeTestFile(t *testing.T, dir, name, data string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	return path
+}
+
+func TestRegistryBuiltins(t *testing.T) {
+	r := NewRegistry()
+	assert.Equal(
+		t,
+		[]corp.SupportedTagset{
+			corp.TagsetCSCNC2000, corp.TagsetCSCNC2000SPK, corp.TagsetCSCNC2020, corp.TagsetUD},
+		r.IDs(),
+	)
+	assert.NoError(t, r.Validate(corp.TagsetUD))
+	assert.NoError(t, r.Validate(""))
+	assert.Error(t, r.Validate("sk_snk"))
+}
+
+func TestRegistryLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "snk.yaml", testYAMLTagset)
+	writeTestFile(t, dir, "test.json", testJSONTagset)
+	writeTestFile(t, dir, "README.txt", "ignored")
+	r := NewRegistry()
+	assert.NoError(t, r.LoadDir(dir))
+
+	schema, ok := r.Get("sk_snk")
+	assert.True(t, ok)
+	assert.Equal(t, 2, schema.Len())
+	assert.NoError(t, schema.Validate("S1"))
+	assert.Error(t, schema.Validate("X1"))
+	assert.Equal(t, []string{"S", "A"}, schema.Positions[1].Values[0].RequiresPOS)
+
+	schema, ok = r.Get("xx_test")
+	assert.True(t, ok)
+	assert.NoError(t, schema.Validate("Nq"))
+
+	q, err := r.BuildTagQuery("sk_snk", corp.Tagset{FeatAttr: "tag"}, Selection{"pos": {"A"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `tag="A.*"`, q.String())
+
+	_, ok = Get("sk_snk")
+	assert.False(t, ok)
+}
+
+func TestRegistryLoadFileUnknownField(t *testing.T) {
+	dir := t.TempDir()
+	r := NewRegistry()
+	path := writeTestFile(t, dir, "bad.yml", testYAMLTagset+"foo: bar\n")
+	assert.Error(t, r.LoadFile(path))
+	path = writeTestFile(t, dir, "bad.json", `{"id": "xx", "positions": [], "foo": 1}`)
+	assert.Error(t, r.LoadFile(path))
+	path = writeTestFile(t, dir, "bad.toml", `id = "xx"`)
+	assert.Error(t, r.LoadFile(path))
+}
+
+func TestRegistryAddInvalid(t *testing.T) {
+	r := NewRegistry()
+	assert.Error(t, r.Add(&Schema{}))
+	assert.Error(t, r.Add(&Schema{ID: "xx"}))
+	assert.Error(t, r.Add(&Schema{ID: corp.TagsetUD, Positions: []Position{{ID: "pos", Open: true}}}))
+	assert.Error(t, r.Add(&Schema{ID: "xx", Positions: []Position{{ID: "pos"}}}))
+	assert.Error(t, r.Add(&Schema{
+		ID:        "xx",
+		Positions: []Position{{ID: "pos", Values: []Value{{Code: "NN"}}}},
+	}))
+	assert.Error(t, r.Add(&Schema{
+		ID:        "xx",
+		Positions: []Position{{ID: "pos", Values: []Value{{Code: "N"}, {Code: "N"}}}},
+	}))
+	assert.Error(t, r.Add(&Schema{
+		ID: "xx",
+		Positions: []Position{
+			{ID: "pos", Values: []Value{{Code: "N"}}},
+			{ID: "pos", Open: true},
+		},
+	}))
+	assert.Error(t, r.Add(&Schema{
+		ID: "xx",
+		Positions: []Position{
+			{ID: "pos", Values: []Value{{Code: "N"}}},
+			{ID: "case", Values: []Value{{Code: "1", RequiresPOS: []string{"V"}}}},
+		},
+	}))
+}
+
+func TestRegistryValidateSetup(t *testing.T) {
+	r := NewRegistry()
+	setup := &corp.CorpusSetup{
+		ID:      "syn2020",
+		Tagsets: []corp.SupportedTagset{corp.TagsetCSCNC2020, corp.TagsetUD},
+	}
+	assert.NoError(t, r.ValidateSetup(setup))
+	setup.Tagsets = append(setup.Tagsets, "sk_snk", "foo")
+	err := r.ValidateSetup(setup)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sk_snk")
+	assert.Contains(t, err.Error(), "foo")
+}
+
+func TestRegistryAsCorpusLoaderValidator(t *testing.T) {
+	tagsetDir := t.TempDir()
+	writeTestFile(t, tagsetDir, "snk.yaml", testYAMLTagset)
+	r := NewRegistry()
+	assert.NoError(t, r.LoadDir(tagsetDir))
+
+	corpDir := t.TempDir()
+	writeTestFile(
+		t, corpDir, "snk.json",
+		`{"id": "snk", "description": {"en": "SNK"}, "MaximumTokenContextWindow": 10, "tagsets": ["sk_snk"]}`,
+	)
+	_, err := corp.LoadDir(corpDir, corp.LoadOptions{})
+	assert.ErrorContains(t, err, "invalid tagset type: sk_snk")
+
+	reg, err := corp.LoadDir(corpDir, corp.LoadOptions{TagsetValidator: r.Validate})
+	assert.NoError(t, err)
+	setup, ok := reg.Get("snk")
+	assert.True(t, ok)
+	assert.Equal(t, []corp.SupportedTagset{"sk_snk"}, setup.Tagsets)
+	assert.NoError(t, r.ValidateSetup(setup))
+}
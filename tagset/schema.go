@@ -0,0 +1,246 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tagset provides descriptions of morphological tagsets
+// used in corpora (see corp.SupportedTagset) and tools for
+// decoding and validating tags.
+package tagset
+
+import (
+	"fmt"
+
+	"github.com/czcorpus/cnc-gokit/collections"
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+const (
+	// NotApplicable is a value of a positional tag position
+	// which does not apply to the word.
+	NotApplicable = "-"
+)
+
+func localeLabel(labels map[string]string, lang string) string {
+	d := labels[lang]
+	if d != "" {
+		return d
+	}
+	return labels["en"]
+}
+
+// Value is a single value of a tag position
+type Value struct {
+	Code string `json:"code" yaml:"code"`
+
+	// Labels contains localized descriptions of the value
+	Labels map[string]string `json:"labels" yaml:"labels"`
+
+	// RequiresPOS lists values of the first (part of speech) position
+	// the value can be combined with. Empty means no restriction.
+	RequiresPOS []string `json:"requiresPos,omitempty" yaml:"requiresPos,omitempty"`
+}
+
+// LocaleLabel returns a localized label of the value.
+// In case the `lang` is not present, "en" version is returned.
+func (v Value) LocaleLabel(lang string) string {
+	return localeLabel(v.Labels, lang)
+}
+
+// Position describes a single position of a positional tag
+type Position struct {
+
+	// ID is a language independent identifier of the position
+	// (e.g. "pos", "gender", "case")
+	ID string `json:"id" yaml:"id"`
+
+	// Labels contains localized names of the position
+	Labels map[string]string `json:"labels" yaml:"labels"`
+
+	Values []Value `json:"values" yaml:"values"`
+
+	// Open means the position values are not enumerated and any
+	// single character is accepted
+	Open bool `json:"open,omitempty" yaml:"open,omitempty"`
+}
+
+// LocaleLabel returns a localized name of the position.
+// In case the `lang` is not present, "en" version is returned.
+func (p Position) LocaleLabel(lang string) string {
+	return localeLabel(p.Labels, lang)
+}
+
+// FindValue returns a value definition for a code. The second
+// returned value is false in case the code is not defined.
+// For open positions, the value is always found.
+func (p Position) FindValue(code string) (Value, bool) {
+	for _, v := range p.Values {
+		if v.Code == code {
+			return v, true
+		}
+	}
+	if p.Open {
+		return Value{Code: code}, true
+	}
+	return Value{}, false
+}
+
+// ------
+
+// Schema describes a positional tagset
+type Schema struct {
+	ID corp.SupportedTagset `json:"id" yaml:"id"`
+
+	// Labels contains localized names of the tagset
+	Labels    map[string]string `json:"labels" yaml:"labels"`
+	Positions []Position        `json:"positions" yaml:"positions"`
+}
+
+// LocaleLabel returns a localized name of the tagset.
+// In case the `lang` is not present, "en" version is returned.
+func (s *Schema) LocaleLabel(lang string) string {
+	return localeLabel(s.Labels, lang)
+}
+
+// Len returns the number of tag positions
+func (s *Schema) Len() int {
+	return len(s.Positions)
+}
+
+// PositionIndex returns an index of a position with the provided ID
+// or -1 if not found.
+func (s *Schema) PositionIndex(id string) int {
+	for i, p := range s.Positions {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ------
+
+// TagError describes an invalid tag. In case the problem is related
+// to a specific position, Position contains its (zero-based) index,
+// otherwise it is -1.
+type TagError struct {
+	Tag      string
+	Position int
+	Msg      string
+}
+
+func (e *TagError) Error() string {
+	if e.Position > -1 {
+		return fmt.Sprintf("invalid tag `%s` at position %d: %s", e.Tag, e.Position+1, e.Msg)
+	}
+	return fmt.Sprintf("invalid tag `%s`: %s", e.Tag, e.Msg)
+}
+
+// Validate tests whether the tag conforms to the schema.
+// The returned error is of type *TagError.
+func (s *Schema) Validate(tag string) error {
+	codes := []rune(tag)
+	if len(codes) != len(s.Positions) {
+		return &TagError{
+			Tag:      tag,
+			Position: -1,
+			Msg:      fmt.Sprintf("expected %d positions, found %d", len(s.Positions), len(codes)),
+		}
+	}
+	for i, p := range s.Positions {
+		v, ok := p.FindValue(string(codes[i]))
+		if !ok {
+			return &TagError{
+				Tag:      tag,
+				Position: i,
+				Msg:      fmt.Sprintf("unknown value `%c` of %s", codes[i], p.ID),
+			}
+		}
+		if len(v.RequiresPOS) > 0 && !collections.SliceContains(v.RequiresPOS, string(codes[0])) {
+			return &TagError{
+				Tag:      tag,
+				Position: i,
+				Msg: fmt.Sprintf(
+					"value `%c` of %s cannot be combined with part of speech `%c`",
+					codes[i], p.ID, codes[0]),
+			}
+		}
+	}
+	return nil
+}
+
+// Feature is a decoded value of a single tag position
+type Feature struct {
+
+	// ID is the position identifier (e.g. "case")
+	ID string `json:"id"`
+
+	// Index is a zero-based index of the position within the tag
+	Index int `json:"index"`
+
+	// Label is a localized name of the position
+	Label string `json:"label"`
+
+	Code string `json:"code"`
+
+	// ValueLabel is a localized description of the value
+	ValueLabel string `json:"valueLabel"`
+}
+
+// Decode validates the tag and decodes it into features. Positions with
+// the NotApplicable value are omitted. Labels are localized using `lang`
+// with "en" as a fallback.
+func (s *Schema) Decode(tag string, lang string) ([]Feature, error) {
+	if err := s.Validate(tag); err != nil {
+		return nil, err
+	}
+	codes := []rune(tag)
+	ans := make([]Feature, 0, len(codes))
+	for i, p := range s.Positions {
+		code := string(codes[i])
+		if code == NotApplicable {
+			continue
+		}
+		v, _ := p.FindValue(code)
+		ans = append(ans, Feature{
+			ID:         p.ID,
+			Index:      i,
+			Label:      p.LocaleLabel(lang),
+			Code:       code,
+			ValueLabel: v.LocaleLabel(lang),
+		})
+	}
+	return ans, nil
+}
+
+// DecodeMap is like Decode but it returns a simple mapping
+// from position IDs to localized value labels.
+func (s *Schema) DecodeMap(tag string, lang string) (map[string]string, error) {
+	feats, err := s.Decode(tag, lang)
+	if err != nil {
+		return nil, err
+	}
+	ans := make(map[string]string, len(feats))
+	for _, f := range feats {
+		ans[f.ID] = f.ValueLabel
+	}
+	return ans, nil
+}
+
+// ------
+
+// labels is a shortcut for creating en+cs label maps
+func labels(en, cs string) map[string]string {
+	return map[string]string{"en": en, "cs": cs}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"testing"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustGet(t *testing.T, ts corp.SupportedTagset) *Schema {
+	s, ok := Get(ts)
+	assert.True(t, ok)
+	return s
+}
+
+func TestSchemaLengths(t *testing.T) {
+	assert.Equal(t, 15, mustGet(t, corp.TagsetCSCNC2000).Len())
+	assert.Equal(t, 16, mustGet(t, corp.TagsetCSCNC2000SPK).Len())
+	assert.Equal(t, 15, mustGet(t, corp.TagsetCSCNC2020).Len())
+	_, ok := Get(corp.TagsetUD)
+	assert.False(t, ok)
+}
+
+func TestDecodeNoun(t *testing.T) {
+	s := mustGet(t, corp.TagsetCSCNC2000)
+	feats, err := s.Decode("NNFS1-----A----", "en")
+	assert.NoError(t, err)
+	assert.Len(t, feats, 6)
+	assert.Equal(t, Feature{ID: "pos", Index: 0, Label: "part of speech", Code: "N", ValueLabel: "noun"}, feats[0])
+	assert.Equal(t, "gender", feats[2].ID)
+	assert.Equal(t, "feminine", feats[2].ValueLabel)
+	assert.Equal(t, "case", feats[4].ID)
+	assert.Equal(t, "nominative", feats[4].ValueLabel)
+
+	m, err := s.DecodeMap("NNFS1-----A----", "cs")
+	assert.NoError(t, err)
+	assert.Equal(t, "ženský", m["gender"])
+	assert.Equal(t, "jednotné", m["number"])
+	assert.Equal(t, "kladný tvar", m["negation"])
+}
+
+func TestDecodeAdjectiveGrade(t *testing.T) {
+	s := mustGet(t, corp.TagsetCSCNC2000)
+	m, err := s.DecodeMap("AAFS1----3A----", "cs")
+	assert.NoError(t, err)
+	assert.Equal(t, "superlativ", m["grade"])
+	m, err = s.DecodeMap("AAFS1----2A----", "cs")
+	assert.NoError(t, err)
+	assert.Equal(t, "komparativ", m["grade"])
+}
+
+func TestDecodeLocaleFallback(t *testing.T) {
+	s := mustGet(t, corp.TagsetCSCNC2000)
+	m, err := s.DecodeMap("Z:-------------", "de")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pos": "punctuation", "subpos": "punctuation"}, m)
+}
+
+func TestDecodeCNC2020Aspect(t *testing.T) {
+	s := mustGet(t, corp.TagsetCSCNC2020)
+	m, err := s.DecodeMap("VB-S---3P-AAI--", "en")
+	assert.NoError(t, err)
+	assert.Equal(t, "imperfective", m["aspect"])
+	assert.Equal(t, "third", m["person"])
+	assert.Error(t, mustGet(t, corp.TagsetCSCNC2000).Validate("VB-S---3P-AAI--"))
+}
+
+func TestValidate(t *testing.T) {
+	s := mustGet(t, corp.TagsetCSCNC2000)
+	for _, tag := range []string{
+		"NNIS2-----A----", "AAMP2----1A----", "RR--4----------", "J^-------------",
+		"C=-------------", "Dg-------1A----", "P7--3----------", "VpYS---XR-AA---",
+	} {
+		assert.NoError(t, s.Validate(tag), tag)
+	}
+	err := s.Validate("NNFS1-----A---")
+	assert.EqualError(t, err, "invalid tag `NNFS1-----A---`: expected 15 positions, found 14")
+
+	err = s.Validate("NNFS8-----A----")
+	tagErr, ok := err.(*TagError)
+	assert.True(t, ok)
+	assert.Equal(t, 4, tagErr.Position)
+
+	err = s.Validate("NVFS1-----A----")
+	assert.EqualError(
+		t, err,
+		"invalid tag `NVFS1-----A----` at position 2: value `V` of subpos cannot be combined with part of speech `N`",
+	)
+}
+
+func TestSpokenOpenPosition(t *testing.T) {
+	s := mustGet(t, corp.TagsetCSCNC2000SPK)
+	m, err := s.DecodeMap("NNFS1-----A----s", "en")
+	assert.NoError(t, err)
+	assert.Equal(t, "", m["spoken"])
+	assert.Contains(t, m, "spoken")
+	assert.Equal(t, 15, s.PositionIndex("spoken"))
+	assert.Equal(t, -1, s.PositionIndex("foo"))
+}
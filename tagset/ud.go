@@ -0,0 +1,149 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/czcorpus/cnc-gokit/collections"
+)
+
+const (
+	// UDEmpty is a placeholder for empty values in CoNLL-U files
+	UDEmpty = "_"
+)
+
+// UPOSTags is a list of the Universal Dependencies part of speech tags
+var UPOSTags = []string{
+	"ADJ", "ADP", "ADV", "AUX", "CCONJ", "DET", "INTJ", "NOUN", "NUM",
+	"PART", "PRON", "PROPN", "PUNCT", "SCONJ", "SYM", "VERB", "X",
+}
+
+var (
+	udFeatNameRegexp  = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*(\[[a-z0-9]+\])?$`)
+	udFeatValueRegexp = regexp.MustCompile(`^[A-Z0-9][A-Za-z0-9]*$`)
+)
+
+// ValidateUPOS tests whether the value is a valid UD part of speech tag
+func ValidateUPOS(upos string) error {
+	if collections.SliceContains(UPOSTags, upos) {
+		return nil
+	}
+	return fmt.Errorf("invalid UPOS tag: %s", upos)
+}
+
+// ------
+
+// Feats represents Universal Dependencies morphological features
+// (the FEATS column of CoNLL-U). A feature can have multiple values
+// (e.g. `Gender=Fem,Neut`).
+type Feats map[string][]string
+
+// ParseFeats parses a FEATS string (e.g. "Case=Nom|Number=Sing").
+// Both the empty string and "_" produce empty features.
+func ParseFeats(s string) (Feats, error) {
+	ans := make(Feats)
+	if s == "" || s == UDEmpty {
+		return ans, nil
+	}
+	for _, item := range strings.Split(s, "|") {
+		name, rawValues, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid UD feature `%s`: missing `=`", item)
+		}
+		if !udFeatNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid UD feature name `%s`", name)
+		}
+		if _, ok := ans[name]; ok {
+			return nil, fmt.Errorf("duplicate UD feature `%s`", name)
+		}
+		values := strings.Split(rawValues, ",")
+		for _, v := range values {
+			if !udFeatValueRegexp.MatchString(v) {
+				return nil, fmt.Errorf("invalid value `%s` of UD feature `%s`", v, name)
+			}
+		}
+		ans[name] = values
+	}
+	return ans, nil
+}
+
+// MustParseFeats is like ParseFeats but it panics in case of an error.
+// It is intended for static definitions.
+func MustParseFeats(s string) Feats {
+	ans, err := ParseFeats(s)
+	if err != nil {
+		panic(err)
+	}
+	return ans
+}
+
+// Get returns values of a feature joined by commas
+// (i.e. as they are written in FEATS).
+func (f Feats) Get(name string) string {
+	return strings.Join(f[name], ",")
+}
+
+// Has tests whether the feature contains the value
+func (f Feats) Has(name, value string) bool {
+	return collections.SliceContains(f[name], value)
+}
+
+// Merge adds all the values from `other`. Values of features present
+// in both are combined.
+func (f Feats) Merge(other Feats) {
+	for k, values := range other {
+		for _, v := range values {
+			if !f.Has(k, v) {
+				f[k] = append(f[k], v)
+			}
+		}
+	}
+}
+
+// Names returns feature names in the canonical UD order
+// (alphabetical, case-insensitive)
+func (f Feats) Names() []string {
+	ans := make([]string, 0, len(f))
+	for k := range f {
+		ans = append(ans, k)
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		return strings.ToLower(ans[i]) < strings.ToLower(ans[j])
+	})
+	return ans
+}
+
+// String serializes the features in the canonical form
+// (features and their values sorted). Empty features produce "_".
+func (f Feats) String() string {
+	if len(f) == 0 {
+		return UDEmpty
+	}
+	items := make([]string, 0, len(f))
+	for _, name := range f.Names() {
+		values := append([]string{}, f[name]...)
+		sort.Slice(values, func(i, j int) bool {
+			return strings.ToLower(values[i]) < strings.ToLower(values[j])
+		})
+		items = append(items, name+"="+strings.Join(values, ","))
+	}
+	return strings.Join(items, "|")
+}
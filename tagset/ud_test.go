@@ -0,0 +1,115 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"testing"
+
+	"github.com/czcorpus/mquery-common/corp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFeats(t *testing.T) {
+	f, err := ParseFeats("Number=Sing|Case=Nom|Gender=Fem,Neut|Gender[psor]=Masc")
+	assert.NoError(t, err)
+	assert.Equal(t, "Nom", f.Get("Case"))
+	assert.Equal(t, "Fem,Neut", f.Get("Gender"))
+	assert.True(t, f.Has("Gender", "Neut"))
+	assert.Equal(t, "Masc", f.Get("Gender[psor]"))
+	assert.Equal(t, "Case=Nom|Gender=Fem,Neut|Gender[psor]=Masc|Number=Sing", f.String())
+}
+
+func TestParseFeatsEmpty(t *testing.T) {
+	for _, s := range []string{"", "_"} {
+		f, err := ParseFeats(s)
+		assert.NoError(t, err)
+		assert.Len(t, f, 0)
+		assert.Equal(t, "_", f.String())
+	}
+}
+
+func TestParseFeatsErrors(t *testing.T) {
+	for _, s := range []string{"Case", "case=Nom", "Case=nom", "Case=Nom|Case=Gen", "Case=Nom||Number=Sing", "Case="} {
+		_, err := ParseFeats(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCanonicalOrderIsCaseInsensitive(t *testing.T) {
+	f := MustParseFeats("VerbForm=Fin|Variant=Short|Abbr=Yes|Gender=Neut,Fem")
+	assert.Equal(t, "Abbr=Yes|Gender=Fem,Neut|Variant=Short|VerbForm=Fin", f.String())
+}
+
+func TestValidateUPOS(t *testing.T) {
+	assert.NoError(t, ValidateUPOS("NOUN"))
+	assert.Error(t, ValidateUPOS("noun"))
+	assert.Error(t, ValidateUPOS("N"))
+}
+
+func TestCNCToUD(t *testing.T) {
+	cases := []struct {
+		ts    corp.SupportedTagset
+		tag   string
+		upos  string
+		feats string
+	}{
+		{corp.TagsetCSCNC2000, "NNFS1-----A----", "NOUN", "Case=Nom|Gender=Fem|Number=Sing|Polarity=Pos"},
+		{corp.TagsetCSCNC2000, "AAIS4----1A----", "ADJ", "Animacy=Inan|Case=Acc|Degree=Pos|Gender=Masc|Number=Sing|Polarity=Pos"},
+		{corp.TagsetCSCNC2000, "RR--3----------", "ADP", "AdpType=Prep|Case=Dat"},
+		{corp.TagsetCSCNC2000, "Z:-------------", "PUNCT", "_"},
+		{corp.TagsetCSCNC2000, "C=-------------", "NUM", "NumForm=Digit|NumType=Card"},
+		{corp.TagsetCSCNC2000, "VpYS---XR-AA---", "VERB", "Gender=Masc|Number=Sing|Polarity=Pos|Tense=Past|VerbForm=Part|Voice=Act"},
+		{corp.TagsetCSCNC2000, "PSZS1FS3-------", "DET", "Case=Nom|Gender=Masc,Neut|Gender[psor]=Fem|Number=Sing|Number[psor]=Sing|Person=3|Poss=Yes|PronType=Prs"},
+		{corp.TagsetCSCNC2020, "VB-S---3P-AAI--", "VERB", "Aspect=Imp|Mood=Ind|Number=Sing|Person=3|Polarity=Pos|Tense=Pres|VerbForm=Fin|Voice=Act"},
+		{corp.TagsetCSCNC2000, "NNFS1-----A---5", "NOUN", "Case=Nom|Gender=Fem|Number=Sing|Polarity=Pos|Style=Coll"},
+	}
+	for _, c := range cases {
+		upos, feats, err := CNCToUD(c.ts, c.tag)
+		assert.NoError(t, err, c.tag)
+		assert.Equal(t, c.upos, upos, c.tag)
+		assert.Equal(t, c.feats, feats.String(), c.tag)
+		assert.NoError(t, ValidateUPOS(upos))
+	}
+}
+
+func TestCNCToUDErrors(t *testing.T) {
+	_, _, err := CNCToUD(corp.TagsetUD, "NOUN")
+	assert.Error(t, err)
+	_, _, err = CNCToUD(corp.TagsetCSCNC2000, "NNFS1")
+	assert.Error(t, err)
+}
+
+func TestAllSubPOSValuesConvertible(t *testing.T) {
+	for _, v := range cncSubPOS().Values {
+		target, ok := udBySubPOS[v.Code]
+		assert.True(t, ok, v.Code)
+		assert.NoError(t, ValidateUPOS(target.upos))
+		_, err := ParseFeats(target.feats)
+		assert.NoError(t, err, v.Code)
+	}
+}
+
+func TestUDLabels(t *testing.T) {
+	assert.Equal(t, "pád", UDFeatureLabel("Case", "cs"))
+	assert.Equal(t, "case", UDFeatureLabel("Case", "de"))
+	assert.Equal(t, "gender [psor]", UDFeatureLabel("Gender[psor]", "en"))
+	assert.Equal(t, "Foo", UDFeatureLabel("Foo", "en"))
+	assert.Equal(t, "slovní druh", UDFeatureLabel(UDPosFeature, "cs"))
+	assert.Equal(t, "vlastní jméno", UDValueLabel(UDPosFeature, "PROPN", "cs"))
+	assert.Equal(t, "feminine, Xyz", UDValueLabel("Gender[psor]", "Fem,Xyz", "en"))
+	assert.Equal(t, "Bar", UDValueLabel("Foo", "Bar", "en"))
+}
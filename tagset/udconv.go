@@ -0,0 +1,235 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"fmt"
+
+	"github.com/czcorpus/mquery-common/corp"
+)
+
+// Conversion from the CNC positional tags to UPOS + FEATS. The tables
+// follow the conventions of the Czech UD treebanks. Please note that
+// some distinctions cannot be derived from the tags alone (e.g. PROPN vs. NOUN
+// or AUX vs. VERB) - in such cases, the more general category is used.
+
+type udTarget struct {
+	upos  string
+	feats string
+}
+
+// udBySubPOS maps the second (detailed POS) position to UPOS
+// and the features implied by it
+var udBySubPOS = map[string]udTarget{
+	"!": {"ADV", "Abbr=Yes"},
+	"#": {"PUNCT", ""},
+	"%": {"PUNCT", ""},
+	"*": {"CCONJ", ""},
+	",": {"SCONJ", ""},
+	".": {"ADJ", "Abbr=Yes"},
+	"0": {"PRON", "PronType=Prs|PrepCase=Pre"},
+	"1": {"DET", "Poss=Yes|PronType=Rel"},
+	"2": {"ADJ", "Hyph=Yes"},
+	"3": {"NUM", "Abbr=Yes"},
+	"4": {"DET", "PronType=Int,Rel"},
+	"5": {"PRON", "PronType=Prs|PrepCase=Pre"},
+	"6": {"PRON", "PronType=Prs|Reflex=Yes"},
+	"7": {"PRON", "PronType=Prs|Reflex=Yes|Variant=Short"},
+	"8": {"DET", "Poss=Yes|PronType=Prs|Reflex=Yes"},
+	"9": {"PRON", "PronType=Rel|PrepCase=Pre"},
+	":": {"PUNCT", ""},
+	";": {"NOUN", "Abbr=Yes"},
+	"=": {"NUM", "NumForm=Digit|NumType=Card"},
+	"?": {"DET", "NumType=Card|PronType=Int,Rel"},
+	"@": {"X", ""},
+	"A": {"ADJ", ""},
+	"B": {"VERB", "Mood=Ind|VerbForm=Fin"},
+	"C": {"ADJ", "Variant=Short"},
+	"D": {"DET", "PronType=Dem"},
+	"E": {"PRON", "PronType=Rel"},
+	"F": {"ADP", "AdpType=Prep"},
+	"G": {"ADJ", "VerbForm=Part|Tense=Pres"},
+	"H": {"PRON", "PronType=Prs|Variant=Short"},
+	"I": {"INTJ", ""},
+	"J": {"PRON", "PronType=Rel"},
+	"K": {"PRON", "PronType=Int,Rel"},
+	"L": {"DET", "PronType=Tot"},
+	"M": {"ADJ", "VerbForm=Part|Tense=Past"},
+	"N": {"NOUN", ""},
+	"O": {"DET", "PronType=Prs"},
+	"P": {"PRON", "PronType=Prs"},
+	"Q": {"PRON", "PronType=Int,Rel"},
+	"R": {"ADP", "AdpType=Prep"},
+	"S": {"DET", "Poss=Yes|PronType=Prs"},
+	"T": {"PART", ""},
+	"U": {"ADJ", "Poss=Yes"},
+	"V": {"ADP", "AdpType=Voc"},
+	"W": {"PRON", "PronType=Neg"},
+	"X": {"X", ""},
+	"Y": {"PRON", "PronType=Int,Rel|Variant=Short"},
+	"Z": {"PRON", "PronType=Ind"},
+	"^": {"CCONJ", ""},
+	"a": {"DET", "NumType=Card|PronType=Ind"},
+	"b": {"ADV", ""},
+	"c": {"AUX", "Mood=Cnd|VerbForm=Fin"},
+	"d": {"NUM", "NumType=Sets"},
+	"e": {"VERB", "Tense=Pres|VerbForm=Conv"},
+	"f": {"VERB", "VerbForm=Inf"},
+	"g": {"ADV", ""},
+	"h": {"NUM", "NumType=Sets"},
+	"i": {"VERB", "Mood=Imp|VerbForm=Fin"},
+	"j": {"NUM", "NumType=Sets"},
+	"k": {"NUM", "NumType=Sets"},
+	"l": {"NUM", "NumType=Card"},
+	"m": {"VERB", "Tense=Past|VerbForm=Conv"},
+	"n": {"NUM", "NumType=Card"},
+	"o": {"ADV", "NumType=Mult|PronType=Ind"},
+	"p": {"VERB", "Tense=Past|VerbForm=Part|Voice=Act"},
+	"q": {"VERB", "Style=Arch|Tense=Past|VerbForm=Part|Voice=Act"},
+	"r": {"ADJ", "NumType=Ord"},
+	"s": {"VERB", "VerbForm=Part|Voice=Pass"},
+	"t": {"VERB", "Mood=Ind|Style=Arch|VerbForm=Fin"},
+	"u": {"ADV", "NumType=Mult|PronType=Int,Rel"},
+	"v": {"ADV", "NumType=Mult"},
+	"w": {"DET", "NumType=Card|PronType=Ind"},
+	"x": {"X", "Abbr=Yes"},
+	"y": {"NUM", "NumType=Frac"},
+	"z": {"ADJ", "NumType=Ord|PronType=Int,Rel"},
+	"}": {"NUM", "NumForm=Roman|NumType=Card"},
+	"~": {"VERB", "Abbr=Yes"},
+}
+
+// udByPosition maps values of individual tag positions (identified
+// by position IDs) to UD features
+var udByPosition = map[string]map[string]string{
+	"gender": {
+		"F": "Gender=Fem",
+		"H": "Gender=Fem,Neut",
+		"I": "Animacy=Inan|Gender=Masc",
+		"M": "Animacy=Anim|Gender=Masc",
+		"N": "Gender=Neut",
+		"Q": "Gender=Fem,Neut",
+		"T": "Animacy=Inan|Gender=Fem,Masc",
+		"Y": "Gender=Masc",
+		"Z": "Gender=Masc,Neut",
+	},
+	"number": {
+		"D": "Number=Dual",
+		"P": "Number=Plur",
+		"S": "Number=Sing",
+		"W": "Number=Plur,Sing",
+	},
+	"case": {
+		"1": "Case=Nom",
+		"2": "Case=Gen",
+		"3": "Case=Dat",
+		"4": "Case=Acc",
+		"5": "Case=Voc",
+		"6": "Case=Loc",
+		"7": "Case=Ins",
+	},
+	"possGender": {
+		"F": "Gender[psor]=Fem",
+		"M": "Gender[psor]=Masc",
+		"Z": "Gender[psor]=Masc,Neut",
+	},
+	"possNumber": {
+		"P": "Number[psor]=Plur",
+		"S": "Number[psor]=Sing",
+	},
+	"person": {
+		"1": "Person=1",
+		"2": "Person=2",
+		"3": "Person=3",
+	},
+	"tense": {
+		"F": "Tense=Fut",
+		"H": "Tense=Past,Pres",
+		"P": "Tense=Pres",
+		"R": "Tense=Past",
+	},
+	"grade": {
+		"1": "Degree=Pos",
+		"2": "Degree=Cmp",
+		"3": "Degree=Sup",
+	},
+	"negation": {
+		"A": "Polarity=Pos",
+		"N": "Polarity=Neg",
+	},
+	"voice": {
+		"A": "Voice=Act",
+		"P": "Voice=Pass",
+	},
+	"aspect": {
+		"B": "Aspect=Imp,Perf",
+		"I": "Aspect=Imp",
+		"P": "Aspect=Perf",
+	},
+	"variant": {
+		"2": "Style=Arch",
+		"3": "Style=Arch",
+		"4": "Style=Arch",
+		"5": "Style=Coll",
+		"6": "Style=Coll",
+		"7": "Style=Coll",
+		"8": "Abbr=Yes",
+	},
+}
+
+// ToUD converts a positional tag to UPOS and UD features. The schema
+// must contain the "subpos" position, other positions are converted
+// based on their IDs (positions unknown to the conversion are ignored).
+func ToUD(schema *Schema, tag string) (string, Feats, error) {
+	if err := schema.Validate(tag); err != nil {
+		return "", nil, err
+	}
+	subposIdx := schema.PositionIndex("subpos")
+	if subposIdx == -1 {
+		return "", nil, fmt.Errorf("tagset %s cannot be converted to UD: missing subpos position", schema.ID)
+	}
+	codes := []rune(tag)
+	target, ok := udBySubPOS[string(codes[subposIdx])]
+	if !ok {
+		return "", nil, fmt.Errorf("no UD conversion for tag `%s`", tag)
+	}
+	feats := MustParseFeats(target.feats)
+	for i, p := range schema.Positions {
+		if mapping, ok := udByPosition[p.ID]; ok {
+			if f, ok := mapping[string(codes[i])]; ok {
+				feats.Merge(MustParseFeats(f))
+			}
+		}
+	}
+	return target.upos, feats, nil
+}
+
+// ToUD converts a tag of a registered positional tagset to UPOS
+// and UD features. See the package-level ToUD for details.
+func (r *Registry) ToUD(ts corp.SupportedTagset, tag string) (string, Feats, error) {
+	schema, ok := r.Get(ts)
+	if !ok {
+		return "", nil, fmt.Errorf("tagset %s cannot be converted to UD", ts)
+	}
+	return ToUD(schema, tag)
+}
+
+// CNCToUD converts a tag of one of the built-in CNC positional tagsets
+// (cs_cnc2000, cs_cnc2000_spk, cs_cnc2020) to UPOS and UD features.
+func CNCToUD(ts corp.SupportedTagset, tag string) (string, Feats, error) {
+	return builtin.ToUD(ts, tag)
+}
@@ -0,0 +1,270 @@
+// Copyright 2026 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2026 Department of Linguistics,
+//                Faculty of Arts, Charles University
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagset
+
+import (
+	"strings"
+)
+
+func udFeature(id, en, cs string, values ...Value) Position {
+	return Position{ID: id, Labels: labels(en, cs), Values: values}
+}
+
+// udFeatures contains labels of the UPOS tags and of the universal
+// features (https://universaldependencies.org/u/feat/index.html).
+// Please note that the positions are used just as a storage of labels,
+// they do not form a positional tagset.
+var udFeatures = map[string]Position{
+	UDPosFeature: udFeature(
+		UDPosFeature, "part of speech", "slovní druh",
+		val("ADJ", "adjective", "adjektivum"),
+		val("ADP", "adposition", "adpozice"),
+		val("ADV", "adverb", "adverbium"),
+		val("AUX", "auxiliary", "pomocné sloveso"),
+		val("CCONJ", "coordinating conjunction", "souřadicí spojka"),
+		val("DET", "determiner", "determinátor"),
+		val("INTJ", "interjection", "citoslovce"),
+		val("NOUN", "noun", "substantivum"),
+		val("NUM", "numeral", "číslovka"),
+		val("PART", "particle", "částice"),
+		val("PRON", "pronoun", "zájmeno"),
+		val("PROPN", "proper noun", "vlastní jméno"),
+		val("PUNCT", "punctuation", "interpunkce"),
+		val("SCONJ", "subordinating conjunction", "podřadicí spojka"),
+		val("SYM", "symbol", "symbol"),
+		val("VERB", "verb", "sloveso"),
+		val("X", "other", "ostatní"),
+	),
+	"PronType": udFeature(
+		"PronType", "pronominal type", "druh zájmena",
+		val("Art", "article", "člen"),
+		val("Dem", "demonstrative", "ukazovací"),
+		val("Emp", "emphatic", "zdůrazňovací"),
+		val("Exc", "exclamative", "zvolací"),
+		val("Ind", "indefinite", "neurčité"),
+		val("Int", "interrogative", "tázací"),
+		val("Neg", "negative", "záporné"),
+		val("Prs", "personal", "osobní"),
+		val("Rcp", "reciprocal", "reciproční"),
+		val("Rel", "relative", "vztažné"),
+		val("Tot", "total", "totalizační"),
+	),
+	"NumType": udFeature(
+		"NumType", "numeral type", "druh číslovky",
+		val("Card", "cardinal", "základní"),
+		val("Dist", "distributive", "distributivní"),
+		val("Frac", "fraction", "zlomková"),
+		val("Mult", "multiplicative", "násobná"),
+		val("Ord", "ordinal", "řadová"),
+		val("Range", "range", "rozsah"),
+		val("Sets", "number of sets", "druhová"),
+	),
+	"Poss":    udFeature("Poss", "possessive", "přivlastňovací", val("Yes", "yes", "ano")),
+	"Reflex":  udFeature("Reflex", "reflexive", "zvratné", val("Yes", "yes", "ano")),
+	"Foreign": udFeature("Foreign", "foreign word", "cizí slovo", val("Yes", "yes", "ano")),
+	"Abbr":    udFeature("Abbr", "abbreviation", "zkratka", val("Yes", "yes", "ano")),
+	"Typo":    udFeature("Typo", "typo", "překlep", val("Yes", "yes", "ano")),
+	"Gender": udFeature(
+		"Gender", "gender", "rod",
+		val("Com", "common", "společný"),
+		val("Fem", "feminine", "ženský"),
+		val("Masc", "masculine", "mužský"),
+		val("Neut", "neuter", "střední"),
+	),
+	"Animacy": udFeature(
+		"Animacy", "animacy", "životnost",
+		val("Anim", "animate", "životný"),
+		val("Hum", "human", "lidský"),
+		val("Inan", "inanimate", "neživotný"),
+		val("Nhum", "non-human", "nelidský"),
+	),
+	"Number": udFeature(
+		"Number", "number", "číslo",
+		val("Coll", "collective", "hromadné"),
+		val("Count", "count plural", "počítací"),
+		val("Dual", "dual", "duál"),
+		val("Grpa", "greater paucal", "větší paukál"),
+		val("Grpl", "greater plural", "větší plurál"),
+		val("Inv", "inverse", "inverzní"),
+		val("Pauc", "paucal", "paukál"),
+		val("Plur", "plural", "plurál"),
+		val("Ptan", "plurale tantum", "pomnožné"),
+		val("Sing", "singular", "singulár"),
+		val("Tri", "trial", "triál"),
+	),
+	"Case": udFeature(
+		"Case", "case", "pád",
+		val("Abs", "absolutive", "absolutiv"),
+		val("Acc", "accusative", "akuzativ"),
+		val("Dat", "dative", "dativ"),
+		val("Erg", "ergative", "ergativ"),
+		val("Gen", "genitive", "genitiv"),
+		val("Ins", "instrumental", "instrumentál"),
+		val("Loc", "locative", "lokál"),
+		val("Nom", "nominative", "nominativ"),
+		val("Voc", "vocative", "vokativ"),
+	),
+	"Definite": udFeature(
+		"Definite", "definiteness", "určenost",
+		val("Com", "complex", "komplexní"),
+		val("Cons", "construct state", "vázaný stav"),
+		val("Def", "definite", "určitý"),
+		val("Ind", "indefinite", "neurčitý"),
+		val("Spec", "specific indefinite", "specifický neurčitý"),
+	),
+	"Degree": udFeature(
+		"Degree", "degree of comparison", "stupeň",
+		val("Abs", "absolute superlative", "absolutní superlativ"),
+		val("Cmp", "comparative", "komparativ"),
+		val("Equ", "equative", "ekvativ"),
+		val("Pos", "positive", "pozitiv"),
+		val("Sup", "superlative", "superlativ"),
+	),
+	"VerbForm": udFeature(
+		"VerbForm", "verb form", "slovesný tvar",
+		val("Conv", "converb", "přechodník"),
+		val("Fin", "finite verb", "určitý tvar"),
+		val("Gdv", "gerundive", "gerundivum"),
+		val("Ger", "gerund", "gerundium"),
+		val("Inf", "infinitive", "infinitiv"),
+		val("Part", "participle", "příčestí"),
+		val("Sup", "supine", "supinum"),
+		val("Vnoun", "verbal noun", "verbální substantivum"),
+	),
+	"Mood": udFeature(
+		"Mood", "mood", "způsob",
+		val("Cnd", "conditional", "podmiňovací"),
+		val("Imp", "imperative", "rozkazovací"),
+		val("Ind", "indicative", "oznamovací"),
+		val("Opt", "optative", "optativ"),
+		val("Pot", "potential", "potenciál"),
+		val("Sub", "subjunctive", "konjunktiv"),
+	),
+	"Tense": udFeature(
+		"Tense", "tense", "čas",
+		val("Fut", "future", "budoucí"),
+		val("Imp", "imperfect", "imperfektum"),
+		val("Past", "past", "minulý"),
+		val("Pqp", "pluperfect", "předminulý"),
+		val("Pres", "present", "přítomný"),
+	),
+	"Aspect": udFeature(
+		"Aspect", "aspect", "vid",
+		val("Hab", "habitual", "habituální"),
+		val("Imp", "imperfective", "nedokonavý"),
+		val("Iter", "iterative", "opakovací"),
+		val("Perf", "perfective", "dokonavý"),
+		val("Prog", "progressive", "průběhový"),
+		val("Prosp", "prospective", "prospektivní"),
+	),
+	"Voice": udFeature(
+		"Voice", "voice", "slovesný rod",
+		val("Act", "active", "činný"),
+		val("Antip", "antipassive", "antipasivum"),
+		val("Cau", "causative", "kauzativ"),
+		val("Dir", "direct", "přímý"),
+		val("Inv", "inverse", "inverzní"),
+		val("Mid", "middle", "mediopasivum"),
+		val("Pass", "passive", "trpný"),
+		val("Rcp", "reciprocal", "reciproční"),
+	),
+	"Polarity": udFeature(
+		"Polarity", "polarity", "negace",
+		val("Neg", "negated", "záporný tvar"),
+		val("Pos", "affirmative", "kladný tvar"),
+	),
+	"Person": udFeature(
+		"Person", "person", "osoba",
+		val("0", "zero", "nultá"),
+		val("1", "first", "první"),
+		val("2", "second", "druhá"),
+		val("3", "third", "třetí"),
+		val("4", "fourth", "čtvrtá"),
+	),
+	"Polite": udFeature(
+		"Polite", "politeness", "zdvořilost",
+		val("Elev", "elevated", "uctivá"),
+		val("Form", "formal", "formální"),
+		val("Humb", "humble", "pokorná"),
+		val("Infm", "informal", "neformální"),
+	),
+	"Evident": udFeature(
+		"Evident", "evidentiality", "evidencialita",
+		val("Fh", "firsthand", "z první ruky"),
+		val("Nfh", "non-firsthand", "zprostředkovaná"),
+	),
+	"Variant": udFeature(
+		"Variant", "variant", "varianta",
+		val("Short", "short form", "krátký tvar"),
+		val("Long", "long form", "dlouhý tvar"),
+	),
+	"Style": udFeature(
+		"Style", "style", "styl",
+		val("Arch", "archaic", "zastaralý"),
+		val("Coll", "colloquial", "hovorový"),
+		val("Expr", "expressive", "expresivní"),
+		val("Form", "formal", "formální"),
+		val("Rare", "rare", "řídký"),
+		val("Slng", "slang", "slangový"),
+		val("Vrnc", "vernacular", "nespisovný"),
+		val("Vulg", "vulgar", "vulgární"),
+	),
+}
+
+// splitLayer splits a layered feature name (e.g. `Gender[psor]`)
+// into the name and the layer
+func splitLayer(name string) (string, string) {
+	base, layer, ok := strings.Cut(name, "[")
+	if !ok {
+		return name, ""
+	}
+	return base, strings.TrimSuffix(layer, "]")
+}
+
+// UDFeatureLabel returns a localized name of a UD feature (or of
+// the UDPosFeature pseudo-feature). For layered features, the layer
+// is appended (e.g. `gender [psor]`). Unknown features are returned
+// as they are.
+func UDFeatureLabel(name, lang string) string {
+	base, layer := splitLayer(name)
+	f, ok := udFeatures[base]
+	if !ok {
+		return name
+	}
+	if layer != "" {
+		return f.LocaleLabel(lang) + " [" + layer + "]"
+	}
+	return f.LocaleLabel(lang)
+}
+
+// UDValueLabel returns a localized description of a UD feature value.
+// Multiple values (e.g. `Fem,Neut`) are described one by one and joined
+// by commas. Unknown values are returned as they are.
+func UDValueLabel(name, value, lang string) string {
+	base, _ := splitLayer(name)
+	f, ok := udFeatures[base]
+	if !ok {
+		return value
+	}
+	values := strings.Split(value, ",")
+	for i, v := range values {
+		if fv, ok := f.FindValue(v); ok {
+			values[i] = fv.LocaleLabel(lang)
+		}
+	}
+	return strings.Join(values, ", ")
+}